@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// idIndexName is the name mongo gives the index of the _id field.
+const idIndexName = "_id_"
+
+// CollectionStats holds the sizes of the collection and its indexes along with the WiredTiger compression stats
+// reported by collStats.
+type CollectionStats struct {
+	// Size is the uncompressed size of the documents.
+	Size           int64 `json:"sizeBytes"`
+	StorageSize    int64 `json:"storageSizeBytes"`
+	TotalIndexSize int64 `json:"totalIndexSizeBytes"`
+	AvgObjSize     int64 `json:"avgObjSizeBytes"`
+	// IndexSizes are keyed by the index name.
+	IndexSizes map[string]int64 `json:"indexSizesBytes"`
+
+	// Compressor is the block compressor of the collection, e.g. snappy, empty when the blocks are not compressed.
+	Compressor             string `json:"compressor"`
+	CompressedPagesRead    int64  `json:"compressedPagesRead"`
+	CompressedPagesWritten int64  `json:"compressedPagesWritten"`
+	// UncompressedPagesWritten counts the pages written as they are, as they failed to compress or were too small.
+	UncompressedPagesWritten int64 `json:"uncompressedPagesWritten"`
+}
+
+// getCollectionStats runs collStats on the collection.
+func (t *Tester) getCollectionStats() (*CollectionStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var document bson.M
+	if err := t.Coll.Database().RunCommand(ctx, bson.D{{Key: "collStats", Value: t.Coll.Name()}}).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to get collection stats: %w", err)
+	}
+
+	indexSizes, ok := document["indexSizes"].(bson.M)
+	if !ok {
+		return nil, fmt.Errorf("indexSizes key not found")
+	}
+	stats := &CollectionStats{
+		Size:           bsonInt64(document["size"]),
+		StorageSize:    bsonInt64(document["storageSize"]),
+		TotalIndexSize: bsonInt64(document["totalIndexSize"]),
+		AvgObjSize:     bsonInt64(document["avgObjSize"]),
+		IndexSizes:     make(map[string]int64, len(indexSizes)),
+	}
+	for name, size := range indexSizes {
+		stats.IndexSizes[name] = bsonInt64(size)
+	}
+
+	// the wiredTiger section is missing with the other storage engines
+	if wt, ok := document["wiredTiger"].(bson.M); ok {
+		stats.Compressor = blockCompressor(wt["creationString"])
+		if compression, ok := wt["compression"].(bson.M); ok {
+			stats.CompressedPagesRead = bsonInt64(compression["compressed pages read"])
+			stats.CompressedPagesWritten = bsonInt64(compression["compressed pages written"])
+			stats.UncompressedPagesWritten = bsonInt64(compression["page written failed to compress"]) +
+				bsonInt64(compression["page written was too small to compress"])
+		}
+	}
+	return stats, nil
+}
+
+// bsonInt64 converts a numeric BSON value to int64, the server reports the numbers either as int32, int64
+// or double depending on their magnitude. It returns zero for the missing and non-numeric values.
+func bsonInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	case primitive.Decimal128:
+		if i, _, err := n.BigInt(); err == nil && i.IsInt64() {
+			return i.Int64()
+		}
+	}
+	return 0
+}
+
+// blockCompressor returns the block_compressor setting of the WiredTiger creation string.
+func blockCompressor(creationString interface{}) string {
+	s, _ := creationString.(string)
+	for _, setting := range strings.Split(s, ",") {
+		if value, ok := strings.CutPrefix(setting, "block_compressor="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// collStatsField is a numeric collection stat reported for every scheme.
+type collStatsField struct {
+	// title names the stat in the table and metric in the measurements.
+	title  string
+	metric string
+	value  func(s *CollectionStats) int64
+}
+
+var collStatsSizeFields = []collStatsField{
+	{"data size", "dataSize", func(s *CollectionStats) int64 { return s.Size }},
+	{"storage size", "storageSize", func(s *CollectionStats) int64 { return s.StorageSize }},
+	{"total index size", "totalIndexSize", func(s *CollectionStats) int64 { return s.TotalIndexSize }},
+	{"avg document size", "avgObjSize", func(s *CollectionStats) int64 { return s.AvgObjSize }},
+}
+
+var collStatsCountFields = []collStatsField{
+	{"compressed pages written", "compressedPagesWritten", func(s *CollectionStats) int64 { return s.CompressedPagesWritten }},
+	{"uncompressed pages written", "uncompressedPagesWritten", func(s *CollectionStats) int64 { return s.UncompressedPagesWritten }},
+}
+
+// collStatsValues returns the values picked from the stats keyed by the scheme name, a sample per trial.
+func collStatsValues(stats map[string][]*CollectionStats, value func(s *CollectionStats) int64) map[string][]int64 {
+	result := make(map[string][]int64, len(stats))
+	for name, trials := range stats {
+		for _, s := range trials {
+			result[name] = append(result[name], value(s))
+		}
+	}
+	return result
+}
+
+// collStatsIndexNames returns the names of the indexes of the stats of all the schemes, the _id index first.
+func collStatsIndexNames(stats map[string][]*CollectionStats) []string {
+	seen := map[string]bool{idIndexName: true}
+	var names []string
+	for _, trials := range stats {
+		for _, s := range trials {
+			for name := range s.IndexSizes {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return append([]string{idIndexName}, names...)
+}
+
+// collStatsIndexSizes returns the sizes of the named index keyed by the scheme name, a sample per trial,
+// the schemes having no such index are left out.
+func collStatsIndexSizes(stats map[string][]*CollectionStats, index string) map[string][]int64 {
+	result := make(map[string][]int64, len(stats))
+	for name, trials := range stats {
+		for _, s := range trials {
+			if size, ok := s.IndexSizes[index]; ok {
+				result[name] = append(result[name], size)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// MetricDelta compares the mean value of a metric in the baseline run with the current one.
+// The lower value is the better one for all the metrics but the throughputs.
+type MetricDelta struct {
+	Scenario string
+	Case     string
+	Metric   string
+	Scheme   string
+	Unit     string
+	Baseline float64
+	Current  float64
+	// DeltaPercent is the change relative to the baseline, positive values are regressions.
+	DeltaPercent float64
+	Regressed    bool
+}
+
+// loadResults reads results saved with the JSON printer.
+func loadResults(path string) (*TesterResults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := new(TesterResults)
+	if err = json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err = validateResults(r); err != nil {
+		return nil, fmt.Errorf("invalid results in %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// validateResults checks the loaded results hold the schemes and no empty scenario results,
+// which the printers and the comparison take for granted.
+func validateResults(r *TesterResults) error {
+	if len(r.Schemes) == 0 {
+		return errors.New("no schemes")
+	}
+	if i := nilIndex(r.InsertsBatched); i >= 0 {
+		return fmt.Errorf("empty insert batches result %d", i)
+	}
+	for i, res := range r.InsertsBatchedWithPresent {
+		if res == nil {
+			return fmt.Errorf("empty insert batches with present result %d", i)
+		}
+		if nilIndex(res.Gets) >= 0 || nilIndex(res.Lookups) >= 0 || nilIndex(res.Pagination) >= 0 || nilIndex(res.RangeQueries) >= 0 {
+			return fmt.Errorf("empty query result of insert batches with present result %d", i)
+		}
+	}
+	if i := nilIndex(r.ConcurrentInserts); i >= 0 {
+		return fmt.Errorf("empty concurrent inserts result %d", i)
+	}
+	if r.UpdatesDeletes != nil {
+		if i := nilIndex(r.UpdatesDeletes.Operations); i >= 0 {
+			return fmt.Errorf("empty updates deletes operation result %d", i)
+		}
+	}
+	for i, res := range r.MixedWorkloads {
+		if res == nil {
+			return fmt.Errorf("empty mixed workload result %d", i)
+		}
+		for op, opRes := range res.Operations {
+			if opRes == nil {
+				return fmt.Errorf("empty %s operation of mixed workload result %d", op, i)
+			}
+		}
+	}
+	if r.SecondaryIndexes != nil {
+		if i := nilIndex(r.SecondaryIndexes.Indexes); i >= 0 {
+			return fmt.Errorf("empty secondary index result %d", i)
+		}
+	}
+	return nil
+}
+
+// nilIndex returns the index of the first nil item, or -1 when there is none.
+func nilIndex[T any](items []*T) int {
+	for i, item := range items {
+		if item == nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// compareResults returns the deltas of the metrics present in both runs, a metric regresses
+// when its mean grows by more than thresholdPercent relative to the baseline.
+// The informational measurements are left out.
+func compareResults(baseline, current *TesterResults, thresholdPercent float64) []MetricDelta {
+	baselineMeans, _ := meanMeasurements(baseline.Measurements())
+	currentMeans, order := meanMeasurements(current.Measurements())
+
+	var result []MetricDelta
+	for _, key := range order {
+		b, ok := baselineMeans[key]
+		if !ok {
+			continue
+		}
+		c := currentMeans[key]
+		if c.Informational {
+			continue
+		}
+
+		d := MetricDelta{
+			Scenario: c.Scenario,
+			Case:     c.Case,
+			Metric:   c.Metric,
+			Scheme:   c.Scheme,
+			Unit:     c.Unit,
+			Baseline: b.Value,
+			Current:  c.Value,
+		}
+		if b.Value != 0 {
+			d.DeltaPercent = (c.Value - b.Value) * 100 / b.Value
+			if c.Unit == unitOpsPerSecond {
+				d.DeltaPercent = (b.Value - c.Value) * 100 / b.Value
+			}
+		}
+		d.Regressed = d.DeltaPercent > thresholdPercent
+		result = append(result, d)
+	}
+	return result
+}
+
+// meanMeasurements averages the measurements of all the trials, the keys are returned in order of appearance.
+func meanMeasurements(measurements []Measurement) (map[string]Measurement, []string) {
+	means := make(map[string]Measurement)
+	counts := make(map[string]int)
+	var order []string
+
+	for _, m := range measurements {
+		key := m.Key()
+		mean, ok := means[key]
+		if !ok {
+			order = append(order, key)
+			mean = m
+			mean.Value = 0
+		}
+		counts[key]++
+		mean.Value += (m.Value - mean.Value) / float64(counts[key])
+		means[key] = mean
+	}
+	return means, order
+}
+
+func hasRegressions(deltas []MetricDelta) bool {
+	for _, d := range deltas {
+		if d.Regressed {
+			return true
+		}
+	}
+	return false
+}
+
+// printComparison writes the deltas as a table in the manner of TablePrinter.
+func printComparison(w io.Writer, baselinePath string, deltas []MetricDelta, thresholdPercent float64) {
+	p := new(TablePrinter)
+
+	header := []string{"Scenario", "Case", "Metric", "Scheme", "Baseline", "Current", "Delta", "Status"}
+	var data [][]string
+	for _, d := range deltas {
+		status := "ok"
+		if d.Regressed {
+			status = "REGRESSION"
+		} else if d.DeltaPercent < -thresholdPercent {
+			status = "improved"
+		}
+		data = append(data, []string{
+			d.Scenario, d.Case, d.Metric, d.Scheme,
+			formatMeasurementValue(d.Baseline, d.Unit),
+			formatMeasurementValue(d.Current, d.Unit),
+			fmt.Sprintf("%+.2f%%", d.DeltaPercent),
+			status,
+		})
+	}
+
+	fmt.Fprintf(w, "\nComparison with the baseline %s, regression threshold %.2f%%\n\n", baselinePath, thresholdPercent)
+	widths := p.columnWidths(header, data)
+	p.printSep(w, widths)
+	p.printRow(w, widths, header)
+	p.printSep(w, widths)
+	for _, row := range data {
+		p.printRow(w, widths, row)
+	}
+	p.printSep(w, widths)
+}
+
+func formatMeasurementValue(v float64, unit string) string {
+	switch unit {
+	case "ns":
+		return time.Duration(v).Round(time.Microsecond).String()
+	case "bytes":
+		return byteCountIEC(int64(v))
+	case "count":
+		return fmt.Sprintf("%.0f", v)
+	case unitOpsPerSecond:
+		return fmt.Sprintf("%.0f %s", v, unitOpsPerSecond)
+	default:
+		return fmt.Sprintf("%g %s", v, unit)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadResults(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"no schemes", `{"schemes": []}`, "no schemes"},
+		{"empty scenario result", `{"schemes": ["ObjectId"], "insertsBatched": [null]}`, "empty insert batches result 0"},
+		{"empty operation result", `{"schemes": ["ObjectId"], "mixedWorkloads": [{"operations": {"read": null}}]}`,
+			"empty read operation of mixed workload result 0"},
+		{"missing operation results", `{"schemes": ["ObjectId", "ULID"], "mixedWorkloads": [{"operations": {}}]}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "results.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			r, err := loadResults(path)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the results without some of the operations are measured and printed all the same
+			r.Measurements()
+			if err = new(TablePrinter).Print(new(strings.Builder), r); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,760 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	scenarioInsertBatches            = "insert-batches"
+	scenarioInserts                  = "inserts"
+	scenarioInsertBatchesWithPresent = "insert-batches-with-present"
+	scenarioConcurrentInserts        = "concurrent-inserts"
+	scenarioUpdatesDeletes           = "updates-deletes"
+	scenarioMixedWorkload            = "mixed-workload"
+	scenarioSecondaryIndexes         = "secondary-indexes"
+	// scenarioAll selects all the scenarios.
+	scenarioAll = "all"
+)
+
+var allScenarios = []string{
+	scenarioInsertBatches,
+	scenarioInserts,
+	scenarioInsertBatchesWithPresent,
+	scenarioConcurrentInserts,
+	scenarioUpdatesDeletes,
+	scenarioMixedWorkload,
+	scenarioSecondaryIndexes,
+}
+
+// defaultScenarios are the scenarios the benchmark started with, the others have to be opted in.
+var defaultScenarios = []string{
+	scenarioInsertBatches,
+	scenarioInserts,
+	scenarioInsertBatchesWithPresent,
+}
+
+// Config defines what the Tester runs and with which document counts.
+type Config struct {
+	// Scenarios lists the scenarios to run, defaultScenarios unless set, or all of them when it holds scenarioAll.
+	Scenarios []string `json:"scenarios" yaml:"scenarios"`
+	// Schemes lists the names of the ID schemes to test, all of them are tested when empty.
+	Schemes []string `json:"schemes" yaml:"schemes"`
+	// Scale is the factor all the document counts are multiplied by.
+	Scale float64 `json:"scale" yaml:"scale"`
+	// Repetitions is the number of trials every scenario is run for.
+	Repetitions int `json:"repetitions" yaml:"repetitions"`
+	// Format is the format of the results: table, json or csv.
+	Format string `json:"format" yaml:"format"`
+	// Output is the path of the file the results are written to, stdout is used when empty.
+	Output string `json:"output" yaml:"output"`
+	// Baseline is the path of results saved in JSON format to compare the current results with.
+	Baseline string `json:"baseline" yaml:"baseline"`
+	// RegressionThreshold is the growth of a metric, in percent of its baseline value,
+	// above which the metric is considered regressed.
+	RegressionThreshold float64 `json:"regressionThreshold" yaml:"regressionThreshold"`
+	// Payload defines the fields the documents of every scheme carry besides the identifier.
+	Payload PayloadConfig `json:"payload" yaml:"payload"`
+	// EngineStats turns on recording the changes of the WiredTiger cache and B-tree counters over every phase.
+	EngineStats bool `json:"engineStats" yaml:"engineStats"`
+
+	InsertBatches            InsertBatchesConfig            `json:"insertBatches" yaml:"insertBatches"`
+	Inserts                  InsertsConfig                  `json:"inserts" yaml:"inserts"`
+	InsertBatchesWithPresent InsertBatchesWithPresentConfig `json:"insertBatchesWithPresent" yaml:"insertBatchesWithPresent"`
+	ConcurrentInserts        ConcurrentInsertsConfig        `json:"concurrentInserts" yaml:"concurrentInserts"`
+	UpdatesDeletes           UpdatesDeletesConfig           `json:"updatesDeletes" yaml:"updatesDeletes"`
+	MixedWorkload            MixedWorkloadConfig            `json:"mixedWorkload" yaml:"mixedWorkload"`
+	SecondaryIndexes         SecondaryIndexesConfig         `json:"secondaryIndexes" yaml:"secondaryIndexes"`
+}
+
+type PayloadConfig struct {
+	// Shape is the shape of the payload: none, padding, nested or template.
+	Shape string `json:"shape" yaml:"shape"`
+	// Size is the approximate size in bytes of the padding and nested payloads.
+	Size int `json:"size" yaml:"size"`
+	// Template is the path of a JSON document the template payloads copy the fields and the value types of.
+	Template string `json:"template" yaml:"template"`
+}
+
+type InsertBatchesConfig struct {
+	TotalDocs  int   `json:"totalDocs" yaml:"totalDocs"`
+	BatchSizes []int `json:"batchSizes" yaml:"batchSizes"`
+}
+
+type InsertsConfig struct {
+	TotalDocs int `json:"totalDocs" yaml:"totalDocs"`
+}
+
+type InsertBatchesWithPresentConfig struct {
+	InsertCount      int   `json:"insertCount" yaml:"insertCount"`
+	PresentCount     int   `json:"presentCount" yaml:"presentCount"`
+	BatchSizes       []int `json:"batchSizes" yaml:"batchSizes"`
+	PrepareBatchSize int   `json:"prepareBatchSize" yaml:"prepareBatchSize"`
+	GetProbes        int   `json:"getProbes" yaml:"getProbes"`
+	// GetDistributions lists the distributions the keys of the gets are picked with among the fixtures followed
+	// by the inserted documents: uniform, zipfian, latest, or latest-N% and oldest-N% for the keys of the N% most recent or oldest documents.
+	GetDistributions []string `json:"getDistributions" yaml:"getDistributions"`
+	// GetIncludeDecode makes the get latencies include decoding the documents into the scheme document type.
+	GetIncludeDecode bool `json:"getIncludeDecode" yaml:"getIncludeDecode"`
+	// RangeProbes is the number of time range queries issued per range width against a collection
+	// of as many documents as are present, created evenly over RangeSpan before now.
+	RangeProbes int        `json:"rangeProbes" yaml:"rangeProbes"`
+	RangeSpan   Duration   `json:"rangeSpan" yaml:"rangeSpan"`
+	RangeWidths []Duration `json:"rangeWidths" yaml:"rangeWidths"`
+	// LookupSizes lists the sizes of the sets of random IDs looked up by a single Find by $in,
+	// LookupProbes lookups are issued for every size.
+	LookupSizes  []int `json:"lookupSizes" yaml:"lookupSizes"`
+	LookupProbes int   `json:"lookupProbes" yaml:"lookupProbes"`
+	// PageSizes lists the sizes of the pages the whole collection is walked in sorted by _id.
+	PageSizes []int `json:"pageSizes" yaml:"pageSizes"`
+}
+
+type ConcurrentInsertsConfig struct {
+	TotalDocs int `json:"totalDocs" yaml:"totalDocs"`
+	BatchSize int `json:"batchSize" yaml:"batchSize"`
+	// Workers lists the numbers of goroutines inserting the documents in parallel.
+	Workers []int `json:"workers" yaml:"workers"`
+	// Generators lists how the ID generators are used by the workers: shared or per-worker.
+	Generators []string `json:"generators" yaml:"generators"`
+}
+
+type SecondaryIndexesConfig struct {
+	TotalDocs int `json:"totalDocs" yaml:"totalDocs"`
+	BatchSize int `json:"batchSize" yaml:"batchSize"`
+	// Tenants is the number of the distinct values of the tenantId field of the documents.
+	Tenants int `json:"tenants" yaml:"tenants"`
+	// Indexes lists the secondary indexes created before the inserts, every index is given by its comma-separated
+	// fields having the direction after a colon, e.g. tenantId:1,_id:-1. The refId field holds the identifier
+	// of another document.
+	Indexes []string `json:"indexes" yaml:"indexes"`
+}
+
+type UpdatesDeletesConfig struct {
+	PresentCount int `json:"presentCount" yaml:"presentCount"`
+	// RecentCount is the number of the most recently inserted documents the recent keys are picked from.
+	RecentCount      int `json:"recentCount" yaml:"recentCount"`
+	PrepareBatchSize int `json:"prepareBatchSize" yaml:"prepareBatchSize"`
+	// Ops is the number of operations of every type per key selection, it is scaled as the document counts.
+	// DeleteMany is called Ops times deleting DeleteManySize documents every time.
+	Ops            int `json:"ops" yaml:"ops"`
+	DeleteManySize int `json:"deleteManySize" yaml:"deleteManySize"`
+}
+
+type MixedWorkloadConfig struct {
+	PresentCount     int `json:"presentCount" yaml:"presentCount"`
+	PrepareBatchSize int `json:"prepareBatchSize" yaml:"prepareBatchSize"`
+	// Duration is the time every scheme runs the workload for.
+	Duration Duration `json:"duration" yaml:"duration"`
+	Workers  int      `json:"workers" yaml:"workers"`
+	// Distributions lists the key distributions the workload is run with, see GetDistributions.
+	Distributions []string    `json:"distributions" yaml:"distributions"`
+	Mix           WorkloadMix `json:"mix" yaml:"mix"`
+	// ScanLength is the number of documents read by every range scan.
+	ScanLength int `json:"scanLength" yaml:"scanLength"`
+}
+
+// WorkloadMix defines the proportions of the operation types in a workload, they do not have to add up to 1.
+type WorkloadMix struct {
+	Insert float64 `json:"insert" yaml:"insert"`
+	Read   float64 `json:"read" yaml:"read"`
+	Update float64 `json:"update" yaml:"update"`
+	Scan   float64 `json:"scan" yaml:"scan"`
+}
+
+func defaultConfig() *Config {
+	const (
+		OneMillion      = 1000000
+		TenMillion      = 10 * OneMillion
+		OneThousand     = 1000
+		FiveThousand    = 5 * OneThousand
+		TenThousand     = 10 * OneThousand
+		HundredThousand = 100 * OneThousand
+	)
+
+	return &Config{
+		Scenarios:           append([]string(nil), defaultScenarios...),
+		Scale:               1,
+		Repetitions:         1,
+		Format:              formatTable,
+		RegressionThreshold: 10,
+		EngineStats:         true,
+		Payload: PayloadConfig{
+			Shape: payloadNone,
+			Size:  OneThousand,
+		},
+		InsertBatches: InsertBatchesConfig{
+			TotalDocs:  OneMillion,
+			BatchSizes: []int{OneThousand, FiveThousand, TenThousand},
+		},
+		Inserts: InsertsConfig{
+			TotalDocs: OneMillion,
+		},
+		InsertBatchesWithPresent: InsertBatchesWithPresentConfig{
+			InsertCount:      TenMillion,
+			PresentCount:     TenMillion,
+			BatchSizes:       []int{TenThousand, HundredThousand},
+			PrepareBatchSize: HundredThousand,
+			GetProbes:        100,
+			GetDistributions: []string{distributionUniform, distributionZipfian, distributionLatestPrefix + "1%", distributionOldestPrefix + "1%"},
+			GetIncludeDecode: true,
+			RangeProbes:      10,
+			RangeSpan:        Duration(30 * 24 * time.Hour),
+			RangeWidths:      []Duration{Duration(time.Minute), Duration(time.Hour), Duration(24 * time.Hour)},
+			LookupSizes:      []int{50, 500},
+			LookupProbes:     20,
+			PageSizes:        []int{OneThousand},
+		},
+		UpdatesDeletes: UpdatesDeletesConfig{
+			PresentCount:     OneMillion,
+			RecentCount:      HundredThousand,
+			PrepareBatchSize: HundredThousand,
+			Ops:              OneThousand,
+			DeleteManySize:   10,
+		},
+		MixedWorkload: MixedWorkloadConfig{
+			PresentCount:     OneMillion,
+			PrepareBatchSize: HundredThousand,
+			Duration:         Duration(30 * time.Second),
+			Workers:          8,
+			Distributions:    []string{distributionUniform, distributionZipfian, distributionLatest},
+			Mix:              WorkloadMix{Insert: 0.15, Read: 0.5, Update: 0.3, Scan: 0.05},
+			ScanLength:       100,
+		},
+		SecondaryIndexes: SecondaryIndexesConfig{
+			TotalDocs: OneMillion,
+			BatchSize: TenThousand,
+			Tenants:   100,
+			Indexes:   []string{indexFieldTenant + ":1,_id:-1", indexFieldRef + ":1"},
+		},
+		ConcurrentInserts: ConcurrentInsertsConfig{
+			TotalDocs:  OneMillion,
+			BatchSize:  OneThousand,
+			Workers:    []int{8, 32},
+			Generators: []string{generatorShared, generatorPerWorker},
+		},
+	}
+}
+
+// mustLoadConfig builds the config from the defaults, the optional config file
+// and the command line flags, the latter taking precedence.
+func mustLoadConfig(args []string) *Config {
+	cfg, err := loadConfig(args)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	return cfg
+}
+
+func loadConfig(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("perftest", flag.ContinueOnError)
+
+	configPath := fs.String("config", "", "path to a JSON or YAML config file")
+	scenarios := fs.String("scenarios", strings.Join(defaultScenarios, ","),
+		"comma-separated scenarios to run, or "+scenarioAll+" of them: "+strings.Join(allScenarios, ", "))
+	schemes := fs.String("schemes", "", "comma-separated ID schemes to test: "+strings.Join(schemeNames(registeredSchemes()), ", "))
+	scale := fs.Float64("scale", 1, "factor all the document counts are multiplied by")
+	repetitions := fs.Int("repetitions", 1, "number of trials every scenario is run for")
+	format := fs.String("format", formatTable, "results format: "+strings.Join(allFormats, ", "))
+	output := fs.String("output", "", "path of the file to write the results to instead of stdout")
+	baseline := fs.String("baseline", "", "path of JSON results to compare the current results with, "+
+		"the exit code is 1 when any metric regresses")
+	regressionThreshold := fs.Float64("regression-threshold", 10, "growth of a metric in percent above which it is considered regressed")
+	engineStats := fs.Bool("engine-stats", true, "record the changes of the WiredTiger cache and B-tree counters over every phase")
+	payload := fs.String("payload", "", "shape of the document payloads: "+strings.Join(allPayloadShapes, ", "))
+	payloadSize := fs.Int("payload-size", 0, "approximate size in bytes of the padding and nested document payloads")
+	payloadTemplate := fs.String("payload-template", "", "path of the JSON document the template payloads copy the fields and the value types of")
+	batchTotal := fs.Int("batch-total", 0, "documents inserted by the insert batches scenario")
+	batchSizes := fs.String("batch-sizes", "", "comma-separated batch sizes of the insert batches scenario")
+	insertTotal := fs.Int("insert-total", 0, "documents inserted by the inserts scenario")
+	presentInsertCount := fs.Int("present-insert-count", 0, "documents inserted by the insert batches with present scenario")
+	presentCount := fs.Int("present-count", 0, "documents present before the insert batches with present scenario")
+	presentBatchSizes := fs.String("present-batch-sizes", "", "comma-separated batch sizes of the insert batches with present scenario")
+	prepareBatchSize := fs.Int("prepare-batch-size", 0, "batch size used to insert the present documents")
+	getProbes := fs.Int("get-probes", 0, "get by ID requests issued by the insert batches with present scenario")
+	getDistributions := fs.String("get-distributions", "", "comma-separated key distributions of the get by ID requests: "+distributionsUsage)
+	getIncludeDecode := fs.Bool("get-include-decode", true, "include decoding the documents into the latencies of the get by ID requests")
+	rangeProbes := fs.Int("range-probes", 0, "time range queries per range width issued by the insert batches with present scenario")
+	rangeSpan := fs.Duration("range-span", 0, "time span the documents of the time range queries are created over")
+	rangeWidths := fs.String("range-widths", "", "comma-separated widths of the time range queries, e.g. 1m,1h,24h")
+	lookupSizes := fs.String("lookup-sizes", "", "comma-separated sizes of the ID sets looked up by $in in the insert batches with present scenario")
+	lookupProbes := fs.Int("lookup-probes", 0, "lookups by $in issued per ID set size by the insert batches with present scenario")
+	pageSizes := fs.String("page-sizes", "", "comma-separated page sizes the collection is walked in by the insert batches with present scenario")
+	updatePresentCount := fs.Int("update-present-count", 0, "documents present before the updates deletes scenario")
+	updateRecentCount := fs.Int("update-recent-count", 0, "most recently inserted documents the recent keys of the updates deletes scenario are picked from")
+	updatePrepareBatchSize := fs.Int("update-prepare-batch-size", 0, "batch size used to insert the documents of the updates deletes scenario")
+	updateOps := fs.Int("update-ops", 0, "operations of every type per key selection issued by the updates deletes scenario")
+	deleteManySize := fs.Int("delete-many-size", 0, "documents deleted by every DeleteMany call of the updates deletes scenario")
+	workloadPresentCount := fs.Int("workload-present-count", 0, "documents present before the mixed workload scenario")
+	workloadDuration := fs.Duration("workload-duration", 0, "time every scheme runs the mixed workload for")
+	workloadWorkers := fs.Int("workload-workers", 0, "workers running the mixed workload")
+	workloadDistributions := fs.String("workload-distributions", "", "comma-separated key distributions of the mixed workload: "+
+		distributionsUsage)
+	workloadMix := fs.String("workload-mix", "", "comma-separated proportions of the mixed workload operations, e.g. insert=0.1,read=0.9,update=0,scan=0")
+	scanLength := fs.Int("scan-length", 0, "documents read by every range scan of the mixed workload")
+	secondaryTotal := fs.Int("secondary-total", 0, "documents inserted by the secondary indexes scenario")
+	secondaryBatchSize := fs.Int("secondary-batch-size", 0, "batch size of the secondary indexes scenario")
+	secondaryTenants := fs.Int("secondary-tenants", 0, "distinct tenants of the documents of the secondary indexes scenario")
+	secondaryIndexes := fs.String("secondary-indexes", "", "semicolon-separated secondary indexes of the secondary indexes scenario, "+
+		"e.g. tenantId:1,_id:-1;refId:1")
+	concurrentTotal := fs.Int("concurrent-total", 0, "documents inserted by the concurrent inserts scenario")
+	concurrentBatchSize := fs.Int("concurrent-batch-size", 0, "batch size of the concurrent inserts scenario")
+	concurrentWorkers := fs.String("concurrent-workers", "", "comma-separated numbers of workers of the concurrent inserts scenario")
+	concurrentGenerators := fs.String("concurrent-generators", "", "comma-separated ID generator modes of the concurrent inserts scenario: "+
+		strings.Join(allGenerators, ", "))
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := defaultConfig()
+	if *configPath != "" {
+		if err := cfg.readFile(*configPath); err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		switch f.Name {
+		case "scenarios":
+			cfg.Scenarios = splitList(*scenarios)
+		case "schemes":
+			cfg.Schemes = splitList(*schemes)
+		case "scale":
+			cfg.Scale = *scale
+		case "repetitions":
+			cfg.Repetitions = *repetitions
+		case "format":
+			cfg.Format = *format
+		case "output":
+			cfg.Output = *output
+		case "baseline":
+			cfg.Baseline = *baseline
+		case "regression-threshold":
+			cfg.RegressionThreshold = *regressionThreshold
+		case "engine-stats":
+			cfg.EngineStats = *engineStats
+		case "payload":
+			cfg.Payload.Shape = *payload
+		case "payload-size":
+			cfg.Payload.Size = *payloadSize
+		case "payload-template":
+			cfg.Payload.Template = *payloadTemplate
+		case "batch-total":
+			cfg.InsertBatches.TotalDocs = *batchTotal
+		case "batch-sizes":
+			cfg.InsertBatches.BatchSizes, err = parseIntList(*batchSizes)
+		case "insert-total":
+			cfg.Inserts.TotalDocs = *insertTotal
+		case "present-insert-count":
+			cfg.InsertBatchesWithPresent.InsertCount = *presentInsertCount
+		case "present-count":
+			cfg.InsertBatchesWithPresent.PresentCount = *presentCount
+		case "present-batch-sizes":
+			cfg.InsertBatchesWithPresent.BatchSizes, err = parseIntList(*presentBatchSizes)
+		case "prepare-batch-size":
+			cfg.InsertBatchesWithPresent.PrepareBatchSize = *prepareBatchSize
+		case "get-probes":
+			cfg.InsertBatchesWithPresent.GetProbes = *getProbes
+		case "get-distributions":
+			cfg.InsertBatchesWithPresent.GetDistributions = splitList(*getDistributions)
+		case "get-include-decode":
+			cfg.InsertBatchesWithPresent.GetIncludeDecode = *getIncludeDecode
+		case "range-probes":
+			cfg.InsertBatchesWithPresent.RangeProbes = *rangeProbes
+		case "range-span":
+			cfg.InsertBatchesWithPresent.RangeSpan = Duration(*rangeSpan)
+		case "range-widths":
+			cfg.InsertBatchesWithPresent.RangeWidths, err = parseDurationList(*rangeWidths)
+		case "lookup-sizes":
+			cfg.InsertBatchesWithPresent.LookupSizes, err = parseIntList(*lookupSizes)
+		case "lookup-probes":
+			cfg.InsertBatchesWithPresent.LookupProbes = *lookupProbes
+		case "page-sizes":
+			cfg.InsertBatchesWithPresent.PageSizes, err = parseIntList(*pageSizes)
+		case "update-present-count":
+			cfg.UpdatesDeletes.PresentCount = *updatePresentCount
+		case "update-recent-count":
+			cfg.UpdatesDeletes.RecentCount = *updateRecentCount
+		case "update-prepare-batch-size":
+			cfg.UpdatesDeletes.PrepareBatchSize = *updatePrepareBatchSize
+		case "update-ops":
+			cfg.UpdatesDeletes.Ops = *updateOps
+		case "delete-many-size":
+			cfg.UpdatesDeletes.DeleteManySize = *deleteManySize
+		case "workload-present-count":
+			cfg.MixedWorkload.PresentCount = *workloadPresentCount
+		case "workload-duration":
+			cfg.MixedWorkload.Duration = Duration(*workloadDuration)
+		case "workload-workers":
+			cfg.MixedWorkload.Workers = *workloadWorkers
+		case "workload-distributions":
+			cfg.MixedWorkload.Distributions = splitList(*workloadDistributions)
+		case "workload-mix":
+			cfg.MixedWorkload.Mix, err = parseWorkloadMix(*workloadMix)
+		case "scan-length":
+			cfg.MixedWorkload.ScanLength = *scanLength
+		case "secondary-total":
+			cfg.SecondaryIndexes.TotalDocs = *secondaryTotal
+		case "secondary-batch-size":
+			cfg.SecondaryIndexes.BatchSize = *secondaryBatchSize
+		case "secondary-tenants":
+			cfg.SecondaryIndexes.Tenants = *secondaryTenants
+		case "secondary-indexes":
+			cfg.SecondaryIndexes.Indexes = splitIndexList(*secondaryIndexes)
+		case "concurrent-total":
+			cfg.ConcurrentInserts.TotalDocs = *concurrentTotal
+		case "concurrent-batch-size":
+			cfg.ConcurrentInserts.BatchSize = *concurrentBatchSize
+		case "concurrent-workers":
+			cfg.ConcurrentInserts.Workers, err = parseIntList(*concurrentWorkers)
+		case "concurrent-generators":
+			cfg.ConcurrentInserts.Generators = splitList(*concurrentGenerators)
+		}
+		if err != nil {
+			err = fmt.Errorf("invalid value of -%s: %w", f.Name, err)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, c)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("unsupported config file extension %q", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) validate() error {
+	if c.Scale <= 0 {
+		return fmt.Errorf("scale must be positive, got %v", c.Scale)
+	}
+	if c.Repetitions < 1 {
+		return fmt.Errorf("repetitions must be positive, got %d", c.Repetitions)
+	}
+	if !contains(allFormats, c.Format) {
+		return fmt.Errorf("unknown output format %q", c.Format)
+	}
+	if c.RegressionThreshold < 0 {
+		return fmt.Errorf("regression threshold must not be negative, got %v", c.RegressionThreshold)
+	}
+	if len(c.Scenarios) == 0 {
+		return errors.New("no scenario to run")
+	}
+	for _, s := range c.Scenarios {
+		if s != scenarioAll && !contains(allScenarios, s) {
+			return fmt.Errorf("unknown scenario %q", s)
+		}
+	}
+	if _, err := selectSchemes(c.Schemes); err != nil {
+		return err
+	}
+	if err := c.Payload.validate(); err != nil {
+		return err
+	}
+	for _, sizes := range [][]int{c.InsertBatches.BatchSizes, c.InsertBatchesWithPresent.BatchSizes, {c.ConcurrentInserts.BatchSize}} {
+		for _, size := range sizes {
+			if size <= 0 {
+				return fmt.Errorf("batch size must be positive, got %d", size)
+			}
+		}
+	}
+	if c.InsertBatchesWithPresent.PrepareBatchSize <= 0 {
+		return fmt.Errorf("prepare batch size must be positive, got %d", c.InsertBatchesWithPresent.PrepareBatchSize)
+	}
+	for _, d := range c.InsertBatchesWithPresent.GetDistributions {
+		if err := validateDistribution(d); err != nil {
+			return err
+		}
+	}
+	for _, size := range c.InsertBatchesWithPresent.PageSizes {
+		if size <= 0 {
+			return fmt.Errorf("page size must be positive, got %d", size)
+		}
+	}
+	for _, size := range c.InsertBatchesWithPresent.LookupSizes {
+		if size <= 0 {
+			return fmt.Errorf("lookup size must be positive, got %d", size)
+		}
+	}
+	if c.InsertBatchesWithPresent.RangeProbes > 0 {
+		span := c.InsertBatchesWithPresent.RangeSpan
+		if span <= 0 {
+			return fmt.Errorf("range span must be positive, got %s", span)
+		}
+		for _, width := range c.InsertBatchesWithPresent.RangeWidths {
+			if width <= 0 || width > span {
+				return fmt.Errorf("range width must be positive and not exceed the range span %s, got %s", span, width)
+			}
+		}
+	}
+	if c.ScenarioEnabled(scenarioUpdatesDeletes) {
+		if err := c.UpdatesDeletes.validate(c); err != nil {
+			return err
+		}
+	}
+	if c.ScenarioEnabled(scenarioMixedWorkload) {
+		if err := c.MixedWorkload.validate(); err != nil {
+			return err
+		}
+	}
+	if c.ScenarioEnabled(scenarioSecondaryIndexes) {
+		if err := c.SecondaryIndexes.validate(); err != nil {
+			return err
+		}
+	}
+	for _, workers := range c.ConcurrentInserts.Workers {
+		if workers <= 0 {
+			return fmt.Errorf("number of workers must be positive, got %d", workers)
+		}
+	}
+	for _, g := range c.ConcurrentInserts.Generators {
+		if !contains(allGenerators, g) {
+			return fmt.Errorf("unknown generator mode %q", g)
+		}
+	}
+	return nil
+}
+
+func (p *PayloadConfig) validate() error {
+	switch p.Shape {
+	case payloadNone:
+	case payloadPadding, payloadNested:
+		if p.Size <= 0 {
+			return fmt.Errorf("payload size must be positive, got %d", p.Size)
+		}
+	case payloadTemplate:
+		if p.Template == "" {
+			return errors.New("payload template path is required by the template payload")
+		}
+	default:
+		return fmt.Errorf("unknown payload shape %q", p.Shape)
+	}
+	return nil
+}
+
+func (u *UpdatesDeletesConfig) validate(c *Config) error {
+	if u.PrepareBatchSize <= 0 {
+		return fmt.Errorf("updates deletes prepare batch size must be positive, got %d", u.PrepareBatchSize)
+	}
+	if u.Ops <= 0 || u.DeleteManySize <= 0 {
+		return fmt.Errorf("updates deletes ops and delete many size must be positive, got %d and %d", u.Ops, u.DeleteManySize)
+	}
+	// every key selection deletes distinct documents
+	deleted := c.Scaled(u.Ops) * (1 + u.DeleteManySize)
+	present, recent := c.Scaled(u.PresentCount), c.Scaled(u.RecentCount)
+	if recent < deleted || present-recent < deleted {
+		return fmt.Errorf("updates deletes need at least %d recent and %d older documents, got %d recent of %d present",
+			deleted, deleted, recent, present)
+	}
+	return nil
+}
+
+func (s *SecondaryIndexesConfig) validate() error {
+	if s.BatchSize <= 0 || s.Tenants <= 0 {
+		return fmt.Errorf("secondary indexes batch size and tenants must be positive, got %d and %d", s.BatchSize, s.Tenants)
+	}
+	for _, spec := range s.Indexes {
+		if _, err := parseIndexKeys(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MixedWorkloadConfig) validate() error {
+	if m.PrepareBatchSize <= 0 || m.Workers <= 0 || m.ScanLength <= 0 {
+		return fmt.Errorf("mixed workload prepare batch size, workers and scan length must be positive, got %d, %d and %d",
+			m.PrepareBatchSize, m.Workers, m.ScanLength)
+	}
+	if m.Duration <= 0 {
+		return fmt.Errorf("mixed workload duration must be positive, got %s", m.Duration)
+	}
+	for _, d := range m.Distributions {
+		if err := validateDistribution(d); err != nil {
+			return err
+		}
+	}
+	mix := m.Mix
+	if mix.Insert < 0 || mix.Read < 0 || mix.Update < 0 || mix.Scan < 0 || mix.total() == 0 {
+		return fmt.Errorf("mixed workload proportions must not be negative and must not all be zero, got %+v", mix)
+	}
+	return nil
+}
+
+// ScenarioEnabled reports whether the scenario has to be run.
+func (c *Config) ScenarioEnabled(name string) bool {
+	return contains(c.Scenarios, scenarioAll) || contains(c.Scenarios, name)
+}
+
+// Scaled applies the scale factor to a document count, keeping at least one document.
+func (c *Config) Scaled(n int) int {
+	scaled := int(float64(n) * c.Scale)
+	if scaled < 1 {
+		return 1
+	}
+	return scaled
+}
+
+func splitList(s string) []string {
+	var result []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// splitIndexList splits the semicolon-separated index specs.
+func splitIndexList(s string) []string {
+	var result []string
+	for _, item := range strings.Split(s, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func parseIntList(s string) ([]int, error) {
+	var result []int
+	for _, item := range splitList(s) {
+		n, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+func parseDurationList(s string) ([]Duration, error) {
+	var result []Duration
+	for _, item := range splitList(s) {
+		d, err := time.ParseDuration(item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, Duration(d))
+	}
+	return result, nil
+}
+
+func parseWorkloadMix(s string) (WorkloadMix, error) {
+	var mix WorkloadMix
+	for _, item := range splitList(s) {
+		op, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return mix, fmt.Errorf("expected operation=proportion, got %q", item)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return mix, err
+		}
+		switch strings.TrimSpace(op) {
+		case workloadInsert:
+			mix.Insert = v
+		case workloadRead:
+			mix.Read = v
+		case workloadUpdate:
+			mix.Update = v
+		case workloadScan:
+			mix.Scan = v
+		default:
+			return mix, fmt.Errorf("unknown operation %q", op)
+		}
+	}
+	return mix, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Duration is a time.Duration written as a string such as "1h30m" in config files.
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (m WorkloadMix) total() float64 {
+	return m.Insert + m.Read + m.Update + m.Scan
+}
+
+// String formats the mix as the -workload-mix flag value.
+func (m WorkloadMix) String() string {
+	return fmt.Sprintf("%s=%g,%s=%g,%s=%g,%s=%g",
+		workloadInsert, m.Insert, workloadRead, m.Read, workloadUpdate, m.Update, workloadScan, m.Scan)
+}
+
+// pick returns a random operation type following the proportions of the mix.
+func (m WorkloadMix) pick(r *rand.Rand) string {
+	v := r.Float64() * m.total()
+	switch {
+	case v < m.Insert:
+		return workloadInsert
+	case v < m.Insert+m.Read:
+		return workloadRead
+	case v < m.Insert+m.Read+m.Update:
+		return workloadUpdate
+	default:
+		return workloadScan
+	}
+}
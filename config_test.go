@@ -0,0 +1,198 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadConfigDefaults(t *testing.T) {
+	cfg, err := loadConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg, defaultConfig()) {
+		t.Fatalf("config without flags differs from the default one:\n%+v\n%+v", cfg, defaultConfig())
+	}
+}
+
+func TestLoadConfigFileAndFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+repetitions: 3
+scale: 0.5
+schemes: [ObjectId, ULID]
+insertBatches:
+  batchSizes: [10, 20]
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig([]string{"-config", path, "-repetitions", "5", "-range-widths", "1m, 2h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the flags take precedence over the file, which takes precedence over the defaults
+	if cfg.Repetitions != 5 {
+		t.Errorf("repetitions = %d, want 5", cfg.Repetitions)
+	}
+	if cfg.Scale != 0.5 {
+		t.Errorf("scale = %v, want 0.5", cfg.Scale)
+	}
+	if want := []string{"ObjectId", "ULID"}; !reflect.DeepEqual(cfg.Schemes, want) {
+		t.Errorf("schemes = %v, want %v", cfg.Schemes, want)
+	}
+	if want := []int{10, 20}; !reflect.DeepEqual(cfg.InsertBatches.BatchSizes, want) {
+		t.Errorf("batch sizes = %v, want %v", cfg.InsertBatches.BatchSizes, want)
+	}
+	if want := []Duration{Duration(time.Minute), Duration(2 * time.Hour)}; !reflect.DeepEqual(cfg.InsertBatchesWithPresent.RangeWidths, want) {
+		t.Errorf("range widths = %v, want %v", cfg.InsertBatchesWithPresent.RangeWidths, want)
+	}
+	if cfg.InsertBatchesWithPresent.PrepareBatchSize != defaultConfig().InsertBatchesWithPresent.PrepareBatchSize {
+		t.Errorf("prepare batch size = %d, want the default one", cfg.InsertBatchesWithPresent.PrepareBatchSize)
+	}
+}
+
+func TestLoadConfigDurations(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+scenarios: [mixed-workload]
+insertBatchesWithPresent:
+  rangeSpan: 72h
+  rangeWidths: [1m, 1h30m]
+mixedWorkload:
+  duration: 30s
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig([]string{"-config", path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MixedWorkload.Duration != Duration(30*time.Second) {
+		t.Errorf("workload duration = %s, want 30s", cfg.MixedWorkload.Duration)
+	}
+	if cfg.InsertBatchesWithPresent.RangeSpan != Duration(72*time.Hour) {
+		t.Errorf("range span = %s, want 72h", cfg.InsertBatchesWithPresent.RangeSpan)
+	}
+	if want := []Duration{Duration(time.Minute), Duration(90 * time.Minute)}; !reflect.DeepEqual(cfg.InsertBatchesWithPresent.RangeWidths, want) {
+		t.Errorf("range widths = %v, want %v", cfg.InsertBatchesWithPresent.RangeWidths, want)
+	}
+
+	// the durations are written as strings and read back unchanged
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "duration: 30s") {
+		t.Errorf("marshaled config holds no \"duration: 30s\":\n%s", out)
+	}
+	roundTripPath := filepath.Join(dir, "round-trip.yaml")
+	if err = os.WriteFile(roundTripPath, out, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := loadConfig([]string{"-config", roundTripPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.MixedWorkload.Duration != cfg.MixedWorkload.Duration ||
+		restored.InsertBatchesWithPresent.RangeSpan != cfg.InsertBatchesWithPresent.RangeSpan ||
+		!reflect.DeepEqual(restored.InsertBatchesWithPresent.RangeWidths, cfg.InsertBatchesWithPresent.RangeWidths) {
+		t.Errorf("durations read back differ from the written ones:\n%+v\n%+v", restored, cfg)
+	}
+
+	// the duration flags take precedence over the file
+	cfg, err = loadConfig([]string{"-config", path, "-workload-duration", "10s", "-range-span", "48h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MixedWorkload.Duration != Duration(10*time.Second) {
+		t.Errorf("workload duration = %s, want the 10s of the flag", cfg.MixedWorkload.Duration)
+	}
+	if cfg.InsertBatchesWithPresent.RangeSpan != Duration(48*time.Hour) {
+		t.Errorf("range span = %s, want the 48h of the flag", cfg.InsertBatchesWithPresent.RangeSpan)
+	}
+	if want := []string{scenarioMixedWorkload}; !reflect.DeepEqual(cfg.Scenarios, want) {
+		t.Errorf("scenarios = %v, want %v of the file", cfg.Scenarios, want)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"-scale", "0"}, "scale must be positive"},
+		{[]string{"-repetitions", "0"}, "repetitions must be positive"},
+		{[]string{"-format", "xml"}, "unknown output format"},
+		{[]string{"-regression-threshold", "-1"}, "regression threshold must not be negative"},
+		{[]string{"-scenarios", "inserts,nope"}, `unknown scenario "nope"`},
+		{[]string{"-schemes", "ObjectId,nope"}, `unknown scheme "nope"`},
+		{[]string{"-payload", "padding", "-payload-size", "0"}, "payload size must be positive"},
+		{[]string{"-payload", "nope"}, "unknown payload shape"},
+		{[]string{"-batch-sizes", "10,0"}, "batch size must be positive"},
+		{[]string{"-batch-sizes", "10,x"}, "invalid value of -batch-sizes"},
+		{[]string{"-range-widths", "1000h"}, "range width must be positive and not exceed the range span"},
+		{[]string{"-get-distributions", "uniform,nope"}, `unknown key distribution "nope"`},
+		{[]string{"-get-distributions", "latest-0%"}, "invalid percentage"},
+		{[]string{"-scenarios", "updates-deletes", "-update-ops", "1000000"}, "updates deletes need at least"},
+		{[]string{"-scenarios", "all", "-secondary-indexes", "tenantId:2"}, "invalid direction"},
+		{[]string{"-scenarios", ""}, "no scenario to run"},
+		{[]string{"-concurrent-generators", "nope"}, `unknown generator mode "nope"`},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			_, err := loadConfig(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestScenarioEnabled(t *testing.T) {
+	cfg := defaultConfig()
+	for _, s := range allScenarios {
+		if got, want := cfg.ScenarioEnabled(s), contains(defaultScenarios, s); got != want {
+			t.Errorf("scenario %s is enabled by default: %v, want %v", s, got, want)
+		}
+	}
+
+	cfg.Scenarios = []string{scenarioAll}
+	for _, s := range allScenarios {
+		if !cfg.ScenarioEnabled(s) {
+			t.Errorf("scenario %s is not enabled by %s", s, scenarioAll)
+		}
+	}
+}
+
+func TestConfigScaled(t *testing.T) {
+	c := &Config{Scale: 0.01}
+	if n := c.Scaled(1000); n != 10 {
+		t.Errorf("Scaled(1000) = %d, want 10", n)
+	}
+	if n := c.Scaled(10); n != 1 {
+		t.Errorf("Scaled(10) = %d, want at least 1", n)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	if got, want := splitList(" a, b,,c ,"), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitList = %v, want %v", got, want)
+	}
+	if got := splitList(""); got != nil {
+		t.Errorf("splitList of empty string = %v, want nil", got)
+	}
+	if got, want := splitIndexList("a:1,b:-1; c:1;"), []string{"a:1,b:-1", "c:1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitIndexList = %v, want %v", got, want)
+	}
+}
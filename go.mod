@@ -3,9 +3,12 @@ module github.com/icamys/mongo.ULID-vs-ObjectID
 go 1.20
 
 require (
-	github.com/google/uuid v1.3.0
+	github.com/google/uuid v1.6.0
 	github.com/oklog/ulid/v2 v2.1.0
+	github.com/rs/xid v1.6.0
+	github.com/segmentio/ksuid v1.0.4
 	go.mongodb.org/mongo-driver v1.11.3
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// histogramSubBucketBits defines the precision of the histogram: every power of two
+// range is split into 2^(histogramSubBucketBits-1) linear sub-buckets, which keeps
+// the relative error of the recorded values under 1/64.
+const histogramSubBucketBits = 7
+
+// Histogram records latencies into log-linear buckets in the manner of HDR histograms,
+// so that percentiles are available at a constant relative precision and memory cost
+// regardless of the number of recorded values.
+type Histogram struct {
+	counts []uint64
+	total  uint64
+	min    time.Duration
+	max    time.Duration
+}
+
+// LatencySummary holds the percentiles of the latencies recorded by a Histogram.
+type LatencySummary struct {
+	Count uint64        `json:"count"`
+	P50   time.Duration `json:"p50Ns"`
+	P90   time.Duration `json:"p90Ns"`
+	P99   time.Duration `json:"p99Ns"`
+	P999  time.Duration `json:"p999Ns"`
+	Max   time.Duration `json:"maxNs"`
+}
+
+// histogramJSON is the serialized form of a Histogram, it carries the summary
+// for the readers of the file and the non-empty buckets to restore the histogram.
+type histogramJSON struct {
+	LatencySummary
+	Min     time.Duration  `json:"minNs"`
+	Buckets map[int]uint64 `json:"buckets"`
+}
+
+func NewHistogram() *Histogram {
+	return new(Histogram)
+}
+
+// Record adds a latency to the histogram, negative latencies are recorded as zero.
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	idx := histogramBucketIndex(uint64(d))
+	if idx >= len(h.counts) {
+		counts := make([]uint64, idx+1)
+		copy(counts, h.counts)
+		h.counts = counts
+	}
+	h.counts[idx]++
+
+	if h.total == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.total++
+}
+
+// Merge adds all the values recorded by other to the histogram.
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil || other.total == 0 {
+		return
+	}
+	if len(other.counts) > len(h.counts) {
+		counts := make([]uint64, len(other.counts))
+		copy(counts, h.counts)
+		h.counts = counts
+	}
+	for i, c := range other.counts {
+		h.counts[i] += c
+	}
+
+	if h.total == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	if other.max > h.max {
+		h.max = other.max
+	}
+	h.total += other.total
+}
+
+// Count returns the number of recorded values.
+func (h *Histogram) Count() uint64 {
+	return h.total
+}
+
+// Percentile returns the value below which the given percentage of the recorded values fall.
+func (h *Histogram) Percentile(p float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(p / 100 * float64(h.total)))
+	if rank < 1 {
+		rank = 1
+	}
+
+	var cumulative uint64
+	for i, c := range h.counts {
+		cumulative += c
+		if cumulative >= rank {
+			v := time.Duration(histogramBucketUpperBound(i))
+			if v > h.max {
+				return h.max
+			}
+			if v < h.min {
+				return h.min
+			}
+			return v
+		}
+	}
+	return h.max
+}
+
+func (h *Histogram) Summary() LatencySummary {
+	return LatencySummary{
+		Count: h.total,
+		P50:   h.Percentile(50),
+		P90:   h.Percentile(90),
+		P99:   h.Percentile(99),
+		P999:  h.Percentile(99.9),
+		Max:   h.max,
+	}
+}
+
+func (h *Histogram) MarshalJSON() ([]byte, error) {
+	v := histogramJSON{
+		LatencySummary: h.Summary(),
+		Min:            h.min,
+		Buckets:        make(map[int]uint64),
+	}
+	for i, c := range h.counts {
+		if c > 0 {
+			v.Buckets[i] = c
+		}
+	}
+	return json.Marshal(v)
+}
+
+func (h *Histogram) UnmarshalJSON(data []byte) error {
+	var v histogramJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*h = Histogram{min: v.Min, max: v.Max}
+	for idx, c := range v.Buckets {
+		if idx < 0 {
+			return fmt.Errorf("invalid histogram bucket %d", idx)
+		}
+		if idx >= len(h.counts) {
+			counts := make([]uint64, idx+1)
+			copy(counts, h.counts)
+			h.counts = counts
+		}
+		h.counts[idx] += c
+		h.total += c
+	}
+	return nil
+}
+
+func histogramBucketIndex(v uint64) int {
+	if v < 1<<histogramSubBucketBits {
+		return int(v)
+	}
+	exp := bits.Len64(v) - histogramSubBucketBits
+	return exp<<(histogramSubBucketBits-1) + int(v>>exp)
+}
+
+func histogramBucketUpperBound(idx int) uint64 {
+	if idx < 1<<histogramSubBucketBits {
+		return uint64(idx)
+	}
+	exp := idx>>(histogramSubBucketBits-1) - 1
+	sub := uint64(idx - exp<<(histogramSubBucketBits-1))
+	return (sub+1)<<exp - 1
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHistogramBucketBounds(t *testing.T) {
+	last := histogramBucketIndex(1 << 62)
+	for idx := 0; idx < last; idx++ {
+		ub := histogramBucketUpperBound(idx)
+		if got := histogramBucketIndex(ub); got != idx {
+			t.Fatalf("upper bound %d of bucket %d falls into bucket %d", ub, idx, got)
+		}
+		// the buckets have no gaps between them
+		if got := histogramBucketIndex(ub + 1); got != idx+1 {
+			t.Fatalf("value %d following bucket %d falls into bucket %d", ub+1, idx, got)
+		}
+	}
+}
+
+func TestHistogramBucketPrecision(t *testing.T) {
+	for _, v := range []uint64{0, 1, 127, 128, 129, 1000, 12345, 999999, 1 << 40, 1<<40 + 12345, math.MaxInt64} {
+		ub := histogramBucketUpperBound(histogramBucketIndex(v))
+		if ub < v {
+			t.Errorf("upper bound %d of the bucket of %d is below it", ub, v)
+		}
+		if ub-v > v/64 {
+			t.Errorf("upper bound %d of the bucket of %d exceeds it by more than 1/64", ub, v)
+		}
+	}
+}
+
+func TestHistogramPercentiles(t *testing.T) {
+	h := NewHistogram()
+	if p := h.Percentile(50); p != 0 {
+		t.Errorf("percentile of an empty histogram = %s, want 0", p)
+	}
+
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Microsecond)
+	}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, time.Microsecond},
+		{50, 500 * time.Microsecond},
+		{90, 900 * time.Microsecond},
+		{99, 990 * time.Microsecond},
+		{100, 1000 * time.Microsecond},
+	}
+	for _, tt := range tests {
+		got := h.Percentile(tt.p)
+		if got < tt.want || got-tt.want > tt.want/64 {
+			t.Errorf("P%v = %s, want %s within 1/64", tt.p, got, tt.want)
+		}
+	}
+	if s := h.Summary(); s.Count != 1000 || s.Max != time.Millisecond {
+		t.Errorf("summary = %+v, want count 1000 and max 1ms", s)
+	}
+
+	h.Record(-time.Second)
+	if p := h.Percentile(0); p != 0 {
+		t.Errorf("negative latency is recorded as %s, want 0", p)
+	}
+}
+
+func TestHistogramMerge(t *testing.T) {
+	a, b, all := NewHistogram(), NewHistogram(), NewHistogram()
+	for i := 1; i <= 100; i++ {
+		d := time.Duration(i*i) * time.Microsecond
+		if i%3 == 0 {
+			a.Record(d)
+		} else {
+			b.Record(d)
+		}
+		all.Record(d)
+	}
+	a.Merge(b)
+	a.Merge(nil)
+	if a.Summary() != all.Summary() || a.min != all.min {
+		t.Errorf("merged summary = %+v, want %+v", a.Summary(), all.Summary())
+	}
+}
+
+func TestHistogramJSON(t *testing.T) {
+	h := NewHistogram()
+	for i := 1; i <= 100; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewHistogram()
+	if err = json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Summary() != h.Summary() || restored.min != h.min {
+		t.Errorf("restored summary = %+v, want %+v", restored.Summary(), h.Summary())
+	}
+
+	if err = json.Unmarshal([]byte(`{"buckets":{"-1":1}}`), restored); err == nil {
+		t.Error("negative bucket is accepted")
+	}
+}
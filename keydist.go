@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+const (
+	distributionUniform = "uniform"
+	distributionZipfian = "zipfian"
+	distributionLatest  = "latest"
+)
+
+// distributionLatestPrefix and distributionOldestPrefix start the names of the distributions picking the keys
+// uniformly among a percentage of the most recent or the oldest keys, e.g. latest-10%.
+const (
+	distributionLatestPrefix = "latest-"
+	distributionOldestPrefix = "oldest-"
+)
+
+// distributionsUsage describes the distribution names in the flags usage.
+var distributionsUsage = strings.Join([]string{
+	distributionUniform, distributionZipfian, distributionLatest,
+	distributionLatestPrefix + "N%", distributionOldestPrefix + "N%",
+}, ", ")
+
+// zipfianConstant is the skew of the zipfian distributions, the one YCSB uses.
+const zipfianConstant = 0.99
+
+// keyChooser picks the keys the operations are run on, the keys are ordered by their insertion time.
+type keyChooser interface {
+	// Next returns the index of a key among n keys.
+	Next(r *rand.Rand, n int) int
+}
+
+// newKeyChooser returns the chooser of the named distribution over initially n keys.
+func newKeyChooser(distribution string, n int) (keyChooser, error) {
+	switch distribution {
+	case distributionUniform:
+		return uniformChooser{}, nil
+	case distributionZipfian:
+		return scrambledZipfianChooser{z: newZipfian(n)}, nil
+	case distributionLatest:
+		return latestChooser{z: newZipfian(n)}, nil
+	}
+
+	for _, prefix := range []string{distributionLatestPrefix, distributionOldestPrefix} {
+		if !strings.HasPrefix(distribution, prefix) {
+			continue
+		}
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimPrefix(distribution, prefix), "%"), 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid percentage of key distribution %q", distribution)
+		}
+		return windowChooser{share: percent / 100, latest: prefix == distributionLatestPrefix}, nil
+	}
+	return nil, fmt.Errorf("unknown key distribution %q", distribution)
+}
+
+// validateDistribution checks that the key distribution name is known.
+func validateDistribution(distribution string) error {
+	_, err := newKeyChooser(distribution, 2)
+	return err
+}
+
+type uniformChooser struct{}
+
+func (uniformChooser) Next(r *rand.Rand, n int) int {
+	return r.Intn(n)
+}
+
+// scrambledZipfianChooser makes some keys much more popular than the others,
+// the popular keys are scattered over the whole key space by hashing.
+type scrambledZipfianChooser struct {
+	z *zipfian
+}
+
+func (c scrambledZipfianChooser) Next(r *rand.Rand, n int) int {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(c.z.Next(r)))
+	h := fnv.New64a()
+	h.Write(b[:])
+	return int(h.Sum64() % uint64(n))
+}
+
+// latestChooser makes the most recently inserted keys the most popular ones.
+type latestChooser struct {
+	z *zipfian
+}
+
+func (c latestChooser) Next(r *rand.Rand, n int) int {
+	i := n - 1 - c.z.Next(r)
+	if i < 0 {
+		return 0
+	}
+	return i
+}
+
+// windowChooser picks the keys uniformly among a share of either the most recent keys or the oldest ones.
+type windowChooser struct {
+	share  float64
+	latest bool
+}
+
+func (c windowChooser) Next(r *rand.Rand, n int) int {
+	w := int(math.Ceil(float64(n) * c.share))
+	if w < 1 {
+		w = 1
+	}
+	i := r.Intn(w)
+	if c.latest {
+		return n - w + i
+	}
+	return i
+}
+
+// zipfian generates integers in [0, items) following the zipfian distribution,
+// 0 being the most popular one, with the algorithm of Gray et al. used by YCSB.
+type zipfian struct {
+	items int
+	theta float64
+	alpha float64
+	zetan float64
+	eta   float64
+}
+
+func newZipfian(items int) *zipfian {
+	if items < 2 {
+		items = 2
+	}
+	theta := zipfianConstant
+	zetan := zeta(items, theta)
+	return &zipfian{
+		items: items,
+		theta: theta,
+		alpha: 1 / (1 - theta),
+		zetan: zetan,
+		eta:   (1 - math.Pow(2/float64(items), 1-theta)) / (1 - zeta(2, theta)/zetan),
+	}
+}
+
+func (z *zipfian) Next(r *rand.Rand) int {
+	u := r.Float64()
+	uz := u * z.zetan
+	if uz < 1 {
+		return 0
+	}
+	if uz < 1+math.Pow(0.5, z.theta) {
+		return 1
+	}
+	return int(float64(z.items) * math.Pow(z.eta*u-z.eta+1, z.alpha))
+}
+
+func zeta(n int, theta float64) float64 {
+	var sum float64
+	for i := 1; i <= n; i++ {
+		sum += 1 / math.Pow(float64(i), theta)
+	}
+	return sum
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// chooserCounts returns how many times every one of n keys was picked by the chooser out of picks.
+func chooserCounts(t *testing.T, c keyChooser, n, picks int) []int {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	counts := make([]int, n)
+	for i := 0; i < picks; i++ {
+		k := c.Next(r, n)
+		if k < 0 || k >= n {
+			t.Fatalf("key %d is out of [0, %d)", k, n)
+		}
+		counts[k]++
+	}
+	return counts
+}
+
+func TestZipfian(t *testing.T) {
+	const items = 1000
+	z := newZipfian(items)
+	r := rand.New(rand.NewSource(1))
+	counts := make([]int, items)
+	for i := 0; i < 100000; i++ {
+		v := z.Next(r)
+		if v < 0 || v >= items {
+			t.Fatalf("value %d is out of [0, %d)", v, items)
+		}
+		counts[v]++
+	}
+	// the zipfian probability of the first item is 1/zeta(items), about 13% of 1000 items
+	if share := float64(counts[0]) / 100000; share < 0.11 || share > 0.15 {
+		t.Errorf("share of the most popular item = %.3f, want about 0.13", share)
+	}
+	if counts[0] <= counts[1] || counts[1] <= counts[10] || counts[10] <= counts[500] {
+		t.Errorf("popularity does not decrease with the rank: %d, %d, %d, %d", counts[0], counts[1], counts[10], counts[500])
+	}
+}
+
+func TestKeyChoosers(t *testing.T) {
+	const n, picks = 1000, 100000
+
+	uniform, err := newKeyChooser(distributionUniform, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := chooserCounts(t, uniform, n, picks)
+	for k, c := range counts {
+		if c < picks/n/2 || c > 2*picks/n {
+			t.Fatalf("uniform key %d is picked %d times, want about %d", k, c, picks/n)
+		}
+	}
+
+	latest, err := newKeyChooser(distributionLatest, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts = chooserCounts(t, latest, n, picks)
+	if counts[n-1] <= counts[n-2] || counts[n-2] <= counts[n/2] {
+		t.Errorf("the latest keys are not the most popular: %d, %d, %d", counts[n-1], counts[n-2], counts[n/2])
+	}
+
+	zipfian, err := newKeyChooser(distributionZipfian, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts = chooserCounts(t, zipfian, n, picks)
+	var top, recent int
+	for k, c := range counts {
+		if c > top {
+			top = c
+		}
+		if k >= n-n/10 {
+			recent += c
+		}
+	}
+	// the popular keys are skewed yet scattered over the key space rather than grouped at its end
+	if top < picks/20 {
+		t.Errorf("the most popular zipfian key is picked %d times, want a skew", top)
+	}
+	if recent > picks/2 {
+		t.Errorf("the latest tenth of the keys is picked %d times out of %d, want the popular keys scattered", recent, picks)
+	}
+
+	// the choosers are made for the initial number of keys yet serve growing ones
+	counts = chooserCounts(t, latest, 2*n, picks)
+	if counts[2*n-1] <= counts[n-1] {
+		t.Errorf("the latest of the grown keys is not the most popular: %d <= %d", counts[2*n-1], counts[n-1])
+	}
+}
+
+func TestUnknownKeyDistribution(t *testing.T) {
+	if _, err := newKeyChooser("pareto", 10); err == nil {
+		t.Error("unknown distribution is accepted")
+	}
+}
+
+func TestWindowChoosers(t *testing.T) {
+	tests := []struct {
+		distribution string
+		n            int
+		from, to     int
+	}{
+		{"latest-10%", 1000, 900, 1000},
+		{"oldest-10%", 1000, 0, 100},
+		{"latest-100%", 1000, 0, 1000},
+		{"oldest-0.5%", 1000, 0, 5},
+		// the window holds a key at least
+		{"latest-1%", 10, 9, 10},
+		{"oldest-1%", 10, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.distribution, func(t *testing.T) {
+			c, err := newKeyChooser(tt.distribution, tt.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, count := range chooserCounts(t, c, tt.n, 10000) {
+				inWindow := k >= tt.from && k < tt.to
+				if inWindow && count == 0 {
+					t.Fatalf("key %d of the window [%d, %d) is never picked", k, tt.from, tt.to)
+				}
+				if !inWindow && count > 0 {
+					t.Fatalf("key %d out of the window [%d, %d) is picked %d times", k, tt.from, tt.to, count)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidWindowDistributions(t *testing.T) {
+	for _, d := range []string{"latest-0%", "oldest-101%", "latest-x%", "latest-", "oldest--5%"} {
+		if err := validateDistribution(d); err == nil {
+			t.Errorf("distribution %q is accepted", d)
+		}
+	}
+	for _, d := range []string{"latest-10%", "oldest-10", "latest-0.1%"} {
+		if err := validateDistribution(d); err != nil {
+			t.Errorf("distribution %q is rejected: %v", d, err)
+		}
+	}
+}
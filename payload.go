@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	payloadNone     = "none"
+	payloadPadding  = "padding"
+	payloadNested   = "nested"
+	payloadTemplate = "template"
+)
+
+var allPayloadShapes = []string{payloadNone, payloadPadding, payloadNested, payloadTemplate}
+
+// reservedPayloadFields are the fields of the documents the payloads must not override.
+var reservedPayloadFields = []string{"_id", "createdAt"}
+
+// Payload holds the fields of a document besides its identifier, they are inlined into the document.
+type Payload map[string]interface{}
+
+// payloadGenerator makes the payloads of the documents of the configured shape,
+// it is safe for concurrent use.
+type payloadGenerator struct {
+	shape string
+	// padding is the length of the padding string making the padding payloads size bytes long.
+	padding int
+	// items is the number of the line items making the nested payloads about size bytes long.
+	items int
+	// template is the JSON document the template payloads copy the fields and the value types of.
+	template map[string]interface{}
+	// size is about the BSON size of the payloads, the timeouts of the writes are scaled with it.
+	size int
+}
+
+// payloadBytesPerSecond is the lowest write rate of the payloads the timeouts of the writes allow for.
+const payloadBytesPerSecond = 10 << 20
+
+func newPayloadGenerator(c PayloadConfig) (*payloadGenerator, error) {
+	g := &payloadGenerator{shape: c.Shape}
+	switch c.Shape {
+	case payloadPadding:
+		empty, err := bson.Marshal(Payload{"padding": ""})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal padding payload: %w", err)
+		}
+		if c.Size > len(empty) {
+			g.padding = c.Size - len(empty)
+		}
+		g.size = len(empty) + g.padding
+	case payloadNested:
+		if err := g.fitNestedItems(c.Size); err != nil {
+			return nil, err
+		}
+		g.size = c.Size
+	case payloadTemplate:
+		data, err := os.ReadFile(c.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payload template: %w", err)
+		}
+		if err = json.Unmarshal(data, &g.template); err != nil {
+			return nil, fmt.Errorf("failed to parse payload template %s: %w", c.Template, err)
+		}
+		for _, field := range reservedPayloadFields {
+			if _, ok := g.template[field]; ok {
+				return nil, fmt.Errorf("payload template must not have the %s field", field)
+			}
+		}
+		doc, err := bson.Marshal(g.template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload template %s: %w", c.Template, err)
+		}
+		g.size = len(doc)
+	}
+	return g, nil
+}
+
+// Next returns a new payload, nil when the documents carry no payload.
+func (g *payloadGenerator) Next() Payload {
+	if g == nil {
+		return nil
+	}
+	switch g.shape {
+	case payloadPadding:
+		return Payload{"padding": randomText(g.padding)}
+	case payloadNested:
+		return nestedPayload(g.items)
+	case payloadTemplate:
+		return Payload(templateValue(g.template).(map[string]interface{}))
+	default:
+		return nil
+	}
+}
+
+// writeTimeout returns the extra time the writes of n documents are given for their payloads,
+// which makes the batches of large documents, e.g. 100k documents of 8 KB, not time out.
+func (g *payloadGenerator) writeTimeout(n int) time.Duration {
+	if g == nil {
+		return 0
+	}
+	return time.Duration(float64(n) * float64(g.size) / payloadBytesPerSecond * float64(time.Second))
+}
+
+// fitNestedItems finds the number of line items making the BSON of the nested payloads about size bytes long.
+func (g *payloadGenerator) fitNestedItems(size int) error {
+	const sampleItems = 10
+	base, err := bson.Marshal(nestedPayload(0))
+	if err != nil {
+		return fmt.Errorf("failed to marshal nested payload: %w", err)
+	}
+	sample, err := bson.Marshal(nestedPayload(sampleItems))
+	if err != nil {
+		return fmt.Errorf("failed to marshal nested payload: %w", err)
+	}
+	if size > len(base) {
+		itemSize := float64(len(sample)-len(base)) / sampleItems
+		g.items = int(math.Round(float64(size-len(base)) / itemSize))
+	}
+	return nil
+}
+
+var payloadStatuses = []string{"active", "pending", "suspended", "archived"}
+
+// nestedPayload makes the fields of a typical order-like document with a subdocument, an array of strings
+// and the given number of line items.
+func nestedPayload(items int) Payload {
+	lines := make([]interface{}, items)
+	for i := range lines {
+		lines[i] = bson.M{
+			"sku":         randomText(12),
+			"quantity":    int32(1 + rand.Intn(10)),
+			"price":       float64(rand.Intn(100000)) / 100,
+			"description": randomText(64),
+		}
+	}
+	tags := make([]interface{}, 3)
+	for i := range tags {
+		tags[i] = randomText(8)
+	}
+	return Payload{
+		"status":    payloadStatuses[rand.Intn(len(payloadStatuses))],
+		"name":      randomText(24),
+		"email":     randomText(12) + "@example.com",
+		"amount":    float64(rand.Intn(10000000)) / 100,
+		"count":     int64(rand.Intn(1000)),
+		"enabled":   rand.Intn(2) == 0,
+		"updatedAt": time.Now().Add(-time.Duration(rand.Int63n(int64(30 * 24 * time.Hour)))),
+		"address": bson.M{
+			"street":  randomText(32),
+			"city":    randomText(12),
+			"zip":     randomText(6),
+			"country": randomText(2),
+		},
+		"tags":  tags,
+		"items": lines,
+	}
+}
+
+// templateValue makes a random value of the type of the JSON value v, strings keep their length,
+// arrays their number of elements and objects their fields.
+func templateValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			result[key] = templateValue(value)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, value := range v {
+			result[i] = templateValue(value)
+		}
+		return result
+	case string:
+		return randomText(len(v))
+	case float64:
+		if v == math.Trunc(v) {
+			return rand.Int63n(int64(math.Abs(v))*2 + 1)
+		}
+		return rand.Float64() * math.Abs(v) * 2
+	case bool:
+		return rand.Intn(2) == 0
+	default:
+		return v
+	}
+}
+
+// payloadText is the text the payload strings are cut from, made of words of lowercase letters
+// to compress like the text of real documents rather than random bytes.
+var payloadText = func() string {
+	const size = 1 << 16
+	r := rand.New(rand.NewSource(1))
+	text := make([]byte, 0, size+16)
+	for len(text) < size {
+		for n := 2 + r.Intn(8); n > 0; n-- {
+			text = append(text, byte('a'+r.Intn(26)))
+		}
+		text = append(text, ' ')
+	}
+	return string(text)
+}()
+
+// randomText returns n characters of the payload text starting at a random position.
+func randomText(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	var result []byte
+	for len(result) < n {
+		start := rand.Intn(len(payloadText))
+		end := start + n - len(result)
+		if end > len(payloadText) {
+			end = len(payloadText)
+		}
+		result = append(result, payloadText[start:end]...)
+	}
+	return string(result)
+}
@@ -3,53 +3,99 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
-	"reflect"
 	"time"
 
-	"github.com/google/uuid"
-	"github.com/oklog/ulid/v2"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/bsoncodec"
-	"go.mongodb.org/mongo-driver/bson/bsonrw"
-	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/mongo"
 	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 func main() {
-	coll, cleanup := mustConnect()
+	if regressed := run(); regressed {
+		os.Exit(1)
+	}
+}
+
+// run runs the test and prints the results, it reports whether any metric
+// regressed compared to the baseline results.
+func run() bool {
+	cfg := mustLoadConfig(os.Args[1:])
+	schemes, err := selectSchemes(cfg.Schemes)
+	if err != nil {
+		panic(err)
+	}
+
+	var baseline *TesterResults
+	if cfg.Baseline != "" {
+		if baseline, err = loadResults(cfg.Baseline); err != nil {
+			panic(fmt.Errorf("failed to load baseline results: %w", err))
+		}
+	}
+
+	payloads, err := newPayloadGenerator(cfg.Payload)
+	if err != nil {
+		panic(err)
+	}
+
+	coll, cleanup := mustConnect(schemes)
 	defer cleanup()
 
 	tester := Tester{
-		Coll: coll,
+		Coll:     coll,
+		Schemes:  schemes,
+		Config:   cfg,
+		Payloads: payloads,
 	}
 
-	start := time.Now()
 	results, err := tester.Run()
 	if err != nil {
 		panic(err)
 	}
-	testDuration := time.Now().Sub(start)
 
-	printer := new(TablePrinter)
-	printer.Print(results)
+	printer, err := newPrinter(cfg.Format)
+	if err != nil {
+		panic(err)
+	}
+
+	out := os.Stdout
+	if cfg.Output != "" {
+		if out, err = os.Create(cfg.Output); err != nil {
+			panic(fmt.Errorf("failed to create output file: %w", err))
+		}
+		defer out.Close()
+	}
+
+	if err = printer.Print(out, results); err != nil {
+		panic(fmt.Errorf("failed to print results: %w", err))
+	}
+
+	if baseline == nil {
+		return false
+	}
 
-	fmt.Printf("\nTotal execution time: %s\n", testDuration.Round(time.Millisecond).String())
+	// keeping machine-readable output clean of the comparison report
+	var report io.Writer = os.Stdout
+	if cfg.Format != formatTable || cfg.Output != "" {
+		report = os.Stderr
+	}
+	deltas := compareResults(baseline, results, cfg.RegressionThreshold)
+	printComparison(report, cfg.Baseline, deltas, cfg.RegressionThreshold)
+	return hasRegressions(deltas)
 }
 
-func mustConnect() (*mongo.Collection, func()) {
+func mustConnect(schemes []IDScheme) (*mongo.Collection, func()) {
 	const timeout = 1 * time.Second
 	ctx := context.Background()
 	uri := os.Getenv("MONGO_URI")
 
-	registry := bson.NewRegistryBuilder().
-		RegisterTypeEncoder(ulidType, bsoncodec.ValueEncoderFunc(ULIDEncodeValue)).
-		RegisterTypeDecoder(ulidType, bsoncodec.ValueDecoderFunc(ULIDDecodeValue)).
-		RegisterTypeEncoder(uuidType, bsoncodec.ValueEncoderFunc(UUIDEncodeValue)).
-		RegisterTypeDecoder(uuidType, bsoncodec.ValueDecoderFunc(UUIDDecodeValue)).
-		Build()
+	registryBuilder := bson.NewRegistryBuilder()
+	for _, s := range schemes {
+		s.RegisterCodecs(registryBuilder)
+	}
+	registry := registryBuilder.Build()
 
 	connCtx, connCancel := context.WithTimeout(ctx, timeout)
 	defer connCancel()
@@ -79,104 +125,3 @@ func mustConnect() (*mongo.Collection, func()) {
 
 	return collection, cleanup
 }
-
-var uuidType = reflect.TypeOf(uuid.UUID{})
-var ulidType = reflect.TypeOf(ulid.ULID{})
-
-func ULIDEncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
-	if !val.IsValid() || val.Type() != ulidType {
-		return bsoncodec.ValueEncoderError{Name: "ULIDEncodeValue", Types: []reflect.Type{ulidType}, Received: val}
-	}
-	b, ok := val.Interface().(ulid.ULID)
-	if !ok {
-		return fmt.Errorf("failed to convert interface of type %s to %s",
-			reflect.TypeOf(val.Interface()).String(), reflect.TypeOf(b))
-	}
-
-	if err := vw.WriteBinaryWithSubtype(b[:], bsontype.BinaryUUID); err != nil {
-		return fmt.Errorf("failed to write binary: %w", err)
-	}
-	return nil
-}
-
-func ULIDDecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
-	if !val.CanSet() || val.Type() != ulidType {
-		return bsoncodec.ValueDecoderError{Name: "ULIDDecodeValue", Types: []reflect.Type{ulidType}, Received: val}
-	}
-
-	var data []byte
-	var subtype byte
-	var err error
-
-	//nolint:exhaustive // the rest of types are covered by the `default` branch
-	switch vrType := vr.Type(); vrType {
-	case bsontype.Binary:
-		data, subtype, err = vr.ReadBinary()
-		if subtype != bsontype.BinaryUUID {
-			return fmt.Errorf("unsupported binary subtype %v for ULID", subtype)
-		}
-	case bsontype.Null:
-		err = vr.ReadNull()
-	case bsontype.Undefined:
-		err = vr.ReadUndefined()
-	default:
-		return fmt.Errorf("cannot decode %v into a ULID", vrType)
-	}
-
-	if err != nil {
-		return fmt.Errorf("failed to read ULID value: %w", err)
-	}
-	val.Set(reflect.ValueOf(ulid.ULID(data)))
-	return nil
-}
-
-func UUIDEncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
-	if !val.IsValid() || val.Type() != uuidType {
-		return bsoncodec.ValueEncoderError{Name: "UUIDEncodeValue", Types: []reflect.Type{uuidType}, Received: val}
-	}
-	b, ok := val.Interface().(uuid.UUID)
-	if !ok {
-		return fmt.Errorf("failed to convert interface of type %s to %s",
-			reflect.TypeOf(val.Interface()).String(), reflect.TypeOf(b))
-	}
-
-	if err := vw.WriteBinaryWithSubtype(b[:], bsontype.BinaryUUID); err != nil {
-		return fmt.Errorf("failed to write binary: %w", err)
-	}
-	return nil
-}
-
-func UUIDDecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
-	if !val.CanSet() || val.Type() != uuidType {
-		return bsoncodec.ValueDecoderError{Name: "UUIDDecodeValue", Types: []reflect.Type{uuidType}, Received: val}
-	}
-
-	var data []byte
-	var subtype byte
-	var err error
-
-	//nolint:exhaustive // the rest of types are covered by the `default` branch
-	switch vrType := vr.Type(); vrType {
-	case bsontype.Binary:
-		data, subtype, err = vr.ReadBinary()
-		if subtype != bsontype.BinaryUUID {
-			return fmt.Errorf("unsupported binary subtype %v for UUID", subtype)
-		}
-	case bsontype.Null:
-		err = vr.ReadNull()
-	case bsontype.Undefined:
-		err = vr.ReadUndefined()
-	default:
-		return fmt.Errorf("cannot decode %v into a UUID", vrType)
-	}
-
-	if err != nil {
-		return fmt.Errorf("failed to read UUID value: %w", err)
-	}
-	uuidBytes, err := uuid.FromBytes(data)
-	if err != nil {
-		return fmt.Errorf("failed to read UUID from bytes: %w", err)
-	}
-	val.Set(reflect.ValueOf(uuidBytes))
-	return nil
-}
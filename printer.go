@@ -1,163 +1,478 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 )
 
+// significanceMarker marks the differences which are statistically significant at the 95% confidence level.
+const significanceMarker = "*"
+
+// Printer writes the test results in a particular format.
+type Printer interface {
+	Print(w io.Writer, r *TesterResults) error
+}
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+	formatHTML  = "html"
+)
+
+var allFormats = []string{formatTable, formatJSON, formatCSV, formatHTML}
+
+func newPrinter(format string) (Printer, error) {
+	switch format {
+	case formatTable:
+		return new(TablePrinter), nil
+	case formatJSON:
+		return new(JSONPrinter), nil
+	case formatCSV:
+		return new(CSVPrinter), nil
+	case formatHTML:
+		return new(HTMLPrinter), nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
 type TablePrinter struct{}
 
-func (p *TablePrinter) Print(r *TesterResults) {
-	var header = []string{"Test case", "ObjectId", "ULID", "UUID", "% diff ULID", "% diff UUID"}
-	var data = [][]string{
-		append([]string{"1M inserts batched, batch size = 1k"}, p.makeRowDataInsertBatches(r.InsertsBatched1M1K)...),
-		append([]string{"1M inserts batched, batch size = 5k"}, p.makeRowDataInsertBatches(r.InsertsBatched1M5K)...),
-		append([]string{"1M inserts batched, batch size = 10k"}, p.makeRowDataInsertBatches(r.InsertsBatched1M10K)...),
-		append([]string{"1M inserts"}, p.makeRowDataInserts(r.Insert1M)...),
-		append(
-			[]string{"10M inserts batched, 10M documents already present, batch size = 10k"},
-			p.makeRowDataInsertBatchesPresInsert(r.InsertsBatchedPres10M10K)...,
-		),
-		append(
-			[]string{"10M inserts batched, 10M documents already present, batch size = 100k"},
-			p.makeRowDataInsertBatchesPresInsert(r.InsertsBatchedPres10M10K)...,
-		),
-		append(
-			[]string{"Index size with 20M docs in bytes"},
-			p.makeRowDataInsertBatchesPresIdxSize(r.InsertsBatchedPres10M10K)...,
-		),
-		append(
-			[]string{"Get by ID from 20M docs, avg duration"},
-			p.makeRowDataInsertBatchesPresGetByID(r.InsertsBatchedPres10M10K)...,
-		),
-	}
-
-	p.printSep()
-	p.printHeader(header)
-	p.printSep()
-	p.printData(data)
-	p.printSep()
-}
-
-func (p *TablePrinter) printHeader(r []string) {
-	fmt.Println(
-		"|", fmt.Sprintf("%-70s", r[0]),
-		"|", fmt.Sprintf("%-10s", r[1]),
-		"|", fmt.Sprintf("%-12s", r[2]),
-		"|", fmt.Sprintf("%-12s", r[3]),
-		"|", fmt.Sprintf("%-12s", r[4]),
-		"|", fmt.Sprintf("%-12s", r[5]),
-		"|")
-}
-
-func (p *TablePrinter) printSep() {
-	fmt.Println(
-		"|", strings.Repeat("-", 70),
-		"|", strings.Repeat("-", 10),
-		"|", strings.Repeat("-", 12),
-		"|", strings.Repeat("-", 12),
-		"|", strings.Repeat("-", 12),
-		"|", strings.Repeat("-", 12),
-		"|")
-}
-
-func (p *TablePrinter) printData(data [][]string) {
+func (p *TablePrinter) Print(w io.Writer, r *TesterResults) error {
+	// the first scheme is the baseline the others are compared with
+	if len(r.Schemes) == 0 {
+		return errors.New("no schemes to print the results of")
+	}
+
+	var header = []string{"Test case"}
+	header = append(header, r.Schemes...)
+	for _, name := range r.Schemes[1:] {
+		header = append(header, "% diff "+name)
+	}
+
+	var data [][]string
+	for _, res := range r.InsertsBatched {
+		data = append(data, append(
+			[]string{fmt.Sprintf("%s inserts batched, batch size = %s", formatCount(res.TotalDocs), formatCount(res.BatchSize))},
+			p.makeRowDataDurations(r.Schemes, res.Durations, time.Millisecond)...,
+		))
+		data = append(data, p.makeRowsEngineStats(
+			fmt.Sprintf("%s inserts batched, batch size = %s", formatCount(res.TotalDocs), formatCount(res.BatchSize)), r.Schemes, res.EngineStats,
+		)...)
+	}
+	if res := r.Inserts; res != nil {
+		data = append(data, append(
+			[]string{fmt.Sprintf("%s inserts", formatCount(res.TotalDocs))},
+			p.makeRowDataDurations(r.Schemes, res.Durations, time.Millisecond)...,
+		))
+		data = append(data, p.makeRowsLatencies(fmt.Sprintf("%s inserts", formatCount(res.TotalDocs)), r.Schemes, res.Latencies)...)
+		data = append(data, p.makeRowsEngineStats(fmt.Sprintf("%s inserts", formatCount(res.TotalDocs)), r.Schemes, res.EngineStats)...)
+	}
+	for _, res := range r.InsertsBatchedWithPresent {
+		data = append(data, append(
+			[]string{fmt.Sprintf(
+				"%s inserts batched, %s documents already present, batch size = %s",
+				formatCount(res.InsertCount), formatCount(res.PresentCount), formatCount(res.BatchSize),
+			)},
+			p.makeRowDataDurations(r.Schemes, res.InsertDurations, time.Millisecond)...,
+		))
+	}
+	for _, res := range r.InsertsBatchedWithPresent {
+		data = append(data, append(
+			[]string{fmt.Sprintf(
+				"Index size with %s docs in bytes, batch size = %s",
+				formatCount(res.InsertCount+res.PresentCount), formatCount(res.BatchSize),
+			)},
+			p.makeRowDataSizes(r.Schemes, res.IdxSizes)...,
+		))
+	}
+	for _, res := range r.InsertsBatchedWithPresent {
+		title := fmt.Sprintf(
+			"Collection with %s docs, batch size = %s",
+			formatCount(res.InsertCount+res.PresentCount), formatCount(res.BatchSize),
+		)
+		data = append(data, p.makeRowsCollectionStats(title, r.Schemes, res.CollStats)...)
+		data = append(data, p.makeRowsEngineStats(title, r.Schemes, res.EngineStats)...)
+		for _, index := range collStatsIndexNames(res.CollStats)[1:] {
+			data = append(data, append(
+				[]string{fmt.Sprintf("%s, index %s size", title, index)},
+				p.makeRowDataSizes(r.Schemes, collStatsIndexSizes(res.CollStats, index))...,
+			))
+		}
+	}
+	for _, res := range r.InsertsBatchedWithPresent {
+		for _, get := range res.Gets {
+			data = append(data, append(
+				[]string{fmt.Sprintf(
+					"Get by ID of %s keys from %s docs, avg duration, batch size = %s",
+					get.Distribution, formatCount(res.InsertCount+res.PresentCount), formatCount(res.BatchSize),
+				)},
+				p.makeRowDataDurations(r.Schemes, get.Durations, time.Microsecond)...,
+			))
+			data = append(data, p.makeRowsLatencies(fmt.Sprintf(
+				"Get by ID of %s keys from %s docs, batch size = %s",
+				get.Distribution, formatCount(res.InsertCount+res.PresentCount), formatCount(res.BatchSize),
+			), r.Schemes, get.Latencies)...)
+		}
+	}
+
+	for _, res := range r.InsertsBatchedWithPresent {
+		for _, lk := range res.Lookups {
+			title := fmt.Sprintf(
+				"Lookup of %d IDs by $in from %s docs, batch size = %s",
+				lk.Size, formatCount(res.InsertCount+res.PresentCount), formatCount(res.BatchSize),
+			)
+			data = append(data, append([]string{title + ", avg duration"}, p.makeRowDataDurations(r.Schemes, lk.Durations, time.Microsecond)...))
+			data = append(data, p.makeRowsLatencies(title, r.Schemes, lk.Latencies)...)
+		}
+	}
+	for _, res := range r.InsertsBatchedWithPresent {
+		for _, pg := range res.Pagination {
+			title := fmt.Sprintf(
+				"Walk %s docs in pages of %s, batch size = %s",
+				formatCount(res.InsertCount+res.PresentCount), formatCount(pg.PageSize), formatCount(res.BatchSize),
+			)
+			data = append(data, append([]string{title}, p.makeRowDataDurations(r.Schemes, pg.WalkDurations, time.Millisecond)...))
+			data = append(data, p.makeRowsLatencies(title+", page", r.Schemes, pg.PageLatencies)...)
+		}
+	}
+	for _, res := range r.InsertsBatchedWithPresent {
+		for _, rq := range res.RangeQueries {
+			title := fmt.Sprintf(
+				"Time range of %s from %s docs, batch size = %s",
+				formatWidth(rq.Width), formatCount(res.InsertCount+res.PresentCount), formatCount(res.BatchSize),
+			)
+			data = append(data, p.makeRowsLatencies(title, r.Schemes, rq.Latencies)...)
+			data = append(data, append([]string{title + ", keys examined"}, p.makeRowDataCounts(r.Schemes, rq.KeysExamined)...))
+			data = append(data, append([]string{title + ", docs returned"}, p.makeRowDataCounts(r.Schemes, rq.DocsReturned)...))
+			data = append(data, append([]string{title + ", field"}, p.makeRowDataStrings(r.Schemes, rq.Fields)...))
+		}
+	}
+	if res := r.UpdatesDeletes; res != nil {
+		for _, op := range res.Operations {
+			title := fmt.Sprintf(
+				"%s by _id of %s keys from %s docs",
+				strings.ToUpper(op.Operation[:1])+op.Operation[1:], op.Selection, formatCount(res.PresentCount),
+			)
+			if op.Operation == operationDeleteMany {
+				title += fmt.Sprintf(", %d docs each", res.DeleteManySize)
+			}
+			data = append(data, append([]string{title + ", avg duration"}, p.makeRowDataDurations(r.Schemes, op.Durations, time.Microsecond)...))
+			data = append(data, p.makeRowsLatencies(title, r.Schemes, op.Latencies)...)
+		}
+		data = append(data, p.makeRowsEngineStats(
+			fmt.Sprintf("Updates and deletes by _id from %s docs", formatCount(res.PresentCount)), r.Schemes, res.EngineStats,
+		)...)
+	}
+	for _, res := range r.MixedWorkloads {
+		title := fmt.Sprintf(
+			"Mixed workload on %s docs, %s keys, %d workers for %s",
+			formatCount(res.PresentCount), res.Distribution, res.Workers, formatWidth(res.Duration),
+		)
+		data = append(data, append([]string{title + ", throughput"}, p.makeRowDataOpsPerSecond(r.Schemes, res.Throughputs)...))
+		for _, op := range allWorkloadOperations {
+			opRes, ok := res.Operations[op]
+			if !ok || opRes == nil {
+				continue
+			}
+			opTitle := fmt.Sprintf("%s, %s", title, op)
+			data = append(data, append([]string{opTitle + " throughput"}, p.makeRowDataOpsPerSecond(r.Schemes, opRes.Throughputs)...))
+			data = append(data, p.makeRowsSelectedLatencies(opTitle, r.Schemes, opRes.Latencies, "p50", "p99")...)
+		}
+		data = append(data, p.makeRowsEngineStats(title, r.Schemes, res.EngineStats)...)
+	}
+	if res := r.SecondaryIndexes; res != nil {
+		title := fmt.Sprintf(
+			"%s inserts batched of %d tenants, batch size = %s",
+			formatCount(res.TotalDocs), res.Tenants, formatCount(res.BatchSize),
+		)
+		data = append(data, append([]string{title + ", _id index only"}, p.makeRowDataDurations(r.Schemes, res.Durations, time.Millisecond)...))
+		data = append(data, append([]string{title + ", with secondary indexes"}, p.makeRowDataDurations(r.Schemes, res.IndexedDurations, time.Millisecond)...))
+		data = append(data, append([]string{title + ", secondary indexes overhead"}, p.makeRowDataDurations(r.Schemes, res.Overheads, time.Millisecond)...))
+		for _, idx := range res.Indexes {
+			data = append(data, append(
+				[]string{fmt.Sprintf("Index {%s} size with %s docs in bytes", idx.Keys, formatCount(res.TotalDocs))},
+				p.makeRowDataSizes(r.Schemes, idx.Sizes)...,
+			))
+		}
+		data = append(data, p.makeRowsCollectionStats(
+			fmt.Sprintf("Collection with %s docs and secondary indexes", formatCount(res.TotalDocs)), r.Schemes, res.CollStats,
+		)...)
+		data = append(data, p.makeRowsEngineStats(title, r.Schemes, res.EngineStats)...)
+	}
+	for _, res := range r.ConcurrentInserts {
+		title := fmt.Sprintf(
+			"%s inserts batched by %d workers, %s generator, batch size = %s",
+			formatCount(res.TotalDocs), res.Workers, res.Generator, formatCount(res.BatchSize),
+		)
+		data = append(data, append([]string{title}, p.makeRowDataDurations(r.Schemes, res.Durations, time.Millisecond)...))
+		data = append(data, append([]string{title + ", throughput"}, p.makeRowDataThroughput(r.Schemes, res.TotalDocs, res.Durations)...))
+		data = append(data, append([]string{title + ", write conflicts"}, p.makeRowDataCounts(r.Schemes, res.WriteConflicts)...))
+		data = append(data, append([]string{title + ", retries"}, p.makeRowDataCounts(r.Schemes, res.Retries)...))
+		data = append(data, p.makeRowsEngineStats(title, r.Schemes, res.EngineStats)...)
+	}
+
+	widths := p.columnWidths(header, data)
+
+	p.printSep(w, widths)
+	p.printRow(w, widths, header)
+	p.printSep(w, widths)
 	for _, row := range data {
-		fmt.Println(
-			"|", fmt.Sprintf("%-70s", row[0]),
-			"|", fmt.Sprintf("%-10s", row[1]),
-			"|", fmt.Sprintf("%-12s", row[2]),
-			"|", fmt.Sprintf("%-12s", row[3]),
-			"|", fmt.Sprintf("%-12s", row[4]),
-			"|", fmt.Sprintf("%-12s", row[5]),
-			"|")
-	}
-}
-
-func (p *TablePrinter) makeRowDataInsertBatches(r *InsertBatchesTestResult) []string {
-	if r == nil {
-		return nil
-	}
-	return []string{
-		r.ObjectIDDuration.Round(1 * time.Millisecond).String(),
-		r.ULIDDuration.Round(1 * time.Millisecond).String(),
-		r.UUIDDuration.Round(1 * time.Millisecond).String(),
-		fmt.Sprintf("%.2f%%", calcDiffPercent(
-			r.ObjectIDDuration.Microseconds(),
-			r.ULIDDuration.Microseconds(),
-		)),
-		fmt.Sprintf("%.2f%%", calcDiffPercent(
-			r.ObjectIDDuration.Microseconds(),
-			r.UUIDDuration.Microseconds(),
-		)),
-	}
-}
-
-func (p *TablePrinter) makeRowDataInsertBatchesPresInsert(r *InsertBatchesWithPresentTestResult) []string {
-	if r == nil {
-		return nil
-	}
-	return []string{
-		r.ObjectIDInsertDuration.Round(1 * time.Millisecond).String(),
-		r.ULIDInsertDuration.Round(1 * time.Millisecond).String(),
-		r.UUIDInsertDuration.Round(1 * time.Millisecond).String(),
-		fmt.Sprintf("%.2f%%", calcDiffPercent(
-			r.ObjectIDInsertDuration.Microseconds(),
-			r.ULIDInsertDuration.Microseconds(),
-		)),
-		fmt.Sprintf("%.2f%%", calcDiffPercent(
-			r.ObjectIDInsertDuration.Microseconds(),
-			r.UUIDInsertDuration.Microseconds(),
-		)),
-	}
-}
-
-func (p *TablePrinter) makeRowDataInsertBatchesPresIdxSize(r *InsertBatchesWithPresentTestResult) []string {
-	if r == nil {
-		return nil
-	}
-	return []string{
-		byteCountIEC(r.ObjectIDIdxSize),
-		byteCountIEC(r.ULIDIdxSize),
-		byteCountIEC(r.UUIDIdxSize),
-		fmt.Sprintf("%.2f%%", calcDiffPercent(r.ObjectIDIdxSize, r.ULIDIdxSize)),
-		fmt.Sprintf("%.2f%%", calcDiffPercent(r.ObjectIDIdxSize, r.UUIDIdxSize)),
-	}
-}
-
-func (p *TablePrinter) makeRowDataInsertBatchesPresGetByID(r *InsertBatchesWithPresentTestResult) []string {
-	if r == nil {
-		return nil
-	}
-	return []string{
-		r.ObjectIDGetDuration.Round(1 * time.Microsecond).String(),
-		r.ULIDGetDuration.Round(1 * time.Microsecond).String(),
-		r.UUIDGetDuration.Round(1 * time.Microsecond).String(),
-		fmt.Sprintf("%.2f%%", calcDiffPercent(
-			r.ObjectIDGetDuration.Microseconds(), r.ULIDGetDuration.Microseconds(),
-		)),
-		fmt.Sprintf("%.2f%%", calcDiffPercent(
-			r.ObjectIDGetDuration.Microseconds(), r.UUIDGetDuration.Microseconds(),
-		)),
-	}
-}
-
-func (p *TablePrinter) makeRowDataInserts(r *InsertTestResult) []string {
-	if r == nil {
-		return nil
-	}
-	return []string{
-		r.ObjectIDDuration.Round(1 * time.Millisecond).String(),
-		r.ULIDDuration.Round(1 * time.Millisecond).String(),
-		r.UUIDDuration.Round(1 * time.Millisecond).String(),
-		fmt.Sprintf("%.2f%%", calcDiffPercent(
-			r.ObjectIDDuration.Microseconds(),
-			r.ULIDDuration.Microseconds(),
-		)),
-		fmt.Sprintf("%.2f%%", calcDiffPercent(
-			r.ObjectIDDuration.Microseconds(),
-			r.UUIDDuration.Microseconds(),
-		)),
+		p.printRow(w, widths, row)
+	}
+	p.printSep(w, widths)
+
+	if r.Repetitions > 1 {
+		fmt.Fprintf(w, "\nValues are means of %d trials ±95%% confidence interval [min..max] σ=standard deviation.\n", r.Repetitions)
+		fmt.Fprintf(w, "%s the difference is statistically significant at 95%% confidence (Welch's t-test).\n", significanceMarker)
+	}
+
+	_, err := fmt.Fprintf(w, "\nTotal execution time: %s\n", r.Metadata.Duration.Round(time.Millisecond).String())
+	return err
+}
+
+// columnWidths returns the width of every column, which is the length of the longest cell in it.
+func (p *TablePrinter) columnWidths(header []string, data [][]string) []int {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, data...) {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	return widths
+}
+
+func (p *TablePrinter) printRow(w io.Writer, widths []int, row []string) {
+	var b strings.Builder
+	for i, width := range widths {
+		var cell string
+		if i < len(row) {
+			cell = row[i]
+		}
+		b.WriteString(fmt.Sprintf("| %-*s ", width, cell))
+	}
+	b.WriteString("|")
+	fmt.Fprintln(w, b.String())
+}
+
+func (p *TablePrinter) printSep(w io.Writer, widths []int) {
+	var b strings.Builder
+	for _, width := range widths {
+		b.WriteString("| " + strings.Repeat("-", width) + " ")
+	}
+	b.WriteString("|")
+	fmt.Fprintln(w, b.String())
+}
+
+// makeRowDataDurations returns the durations of all the schemes followed by
+// the difference between each scheme and the baseline one.
+func (p *TablePrinter) makeRowDataDurations(schemes []string, d map[string][]time.Duration, precision time.Duration) []string {
+	samples := make(map[string][]float64, len(d))
+	for name, durations := range d {
+		samples[name] = durationSamples(durations)
+	}
+	return p.makeRowData(schemes, samples, func(v float64) string {
+		return time.Duration(v).Round(precision).String()
+	})
+}
+
+// makeRowDataSizes returns the sizes of all the schemes followed by
+// the difference between each scheme and the baseline one.
+func (p *TablePrinter) makeRowDataSizes(schemes []string, s map[string][]int64) []string {
+	samples := make(map[string][]float64, len(s))
+	for name, sizes := range s {
+		samples[name] = int64Samples(sizes)
+	}
+	return p.makeRowData(schemes, samples, func(v float64) string {
+		return byteCountIEC(int64(v))
+	})
+}
+
+// makeRowDataThroughput returns the documents inserted per second by all the schemes followed by
+// the difference between each scheme and the baseline one.
+func (p *TablePrinter) makeRowDataThroughput(schemes []string, docs int, d map[string][]time.Duration) []string {
+	samples := make(map[string][]float64, len(d))
+	for name, durations := range d {
+		for _, duration := range durations {
+			samples[name] = append(samples[name], float64(docs)/duration.Seconds())
+		}
+	}
+	return p.makeRowDataDiff(schemes, samples, func(v float64) string {
+		return fmt.Sprintf("%.0f docs/s", v)
+	}, calcRateDiffPercent)
+}
+
+// makeRowDataOpsPerSecond returns the operations per second of all the schemes followed by
+// the difference between each scheme and the baseline one.
+func (p *TablePrinter) makeRowDataOpsPerSecond(schemes []string, samples map[string][]float64) []string {
+	return p.makeRowDataDiff(schemes, samples, func(v float64) string {
+		return fmt.Sprintf("%.0f ops/s", v)
+	}, calcRateDiffPercent)
+}
+
+// makeRowDataCounts returns the counts of all the schemes followed by
+// the difference between each scheme and the baseline one.
+func (p *TablePrinter) makeRowDataCounts(schemes []string, c map[string][]int64) []string {
+	samples := make(map[string][]float64, len(c))
+	for name, counts := range c {
+		samples[name] = int64Samples(counts)
+	}
+	return p.makeRowData(schemes, samples, func(v float64) string {
+		return fmt.Sprintf("%.0f", v)
+	})
+}
+
+// makeRowDataStrings returns the values of all the schemes as they are, leaving the differences empty.
+func (p *TablePrinter) makeRowDataStrings(schemes []string, v map[string]string) []string {
+	row := make([]string, 2*len(schemes)-1)
+	for i, name := range schemes {
+		row[i] = v[name]
+	}
+	return row
+}
+
+// makeRowsCollectionStats returns a row per collection size and compression stat of all the schemes.
+func (p *TablePrinter) makeRowsCollectionStats(title string, schemes []string, stats map[string][]*CollectionStats) [][]string {
+	var rows [][]string
+	for _, f := range collStatsSizeFields {
+		rows = append(rows, append([]string{title + ", " + f.title}, p.makeRowDataSizes(schemes, collStatsValues(stats, f.value))...))
+	}
+	for _, f := range collStatsCountFields {
+		rows = append(rows, append([]string{title + ", " + f.title}, p.makeRowDataCounts(schemes, collStatsValues(stats, f.value))...))
+	}
+
+	compressors := make(map[string]string, len(stats))
+	for name, trials := range stats {
+		if len(trials) > 0 {
+			compressors[name] = trials[0].Compressor
+		}
+	}
+	return append(rows, append([]string{title + ", compressor"}, p.makeRowDataStrings(schemes, compressors)...))
+}
+
+// makeRowsEngineStats returns a row per WiredTiger counter of the B-trees of the collection of every phase.
+func (p *TablePrinter) makeRowsEngineStats(title string, schemes []string, stats []*PhaseEngineStats) [][]string {
+	var rows [][]string
+	for _, ps := range stats {
+		for _, f := range engineStatsFields {
+			rowTitle := fmt.Sprintf("%s, %s phase, %s", title, ps.Phase, f.title)
+			values := engineStatsValues(ps.Collection, f.value)
+			if f.bytes {
+				rows = append(rows, append([]string{rowTitle}, p.makeRowDataSizes(schemes, values)...))
+			} else {
+				rows = append(rows, append([]string{rowTitle}, p.makeRowDataCounts(schemes, values)...))
+			}
+		}
+	}
+	return rows
+}
+
+// makeRowsLatencies returns a row per latency percentile of all the schemes.
+func (p *TablePrinter) makeRowsLatencies(title string, schemes []string, h map[string]*Histogram) [][]string {
+	return p.makeRowsSelectedLatencies(title, schemes, h, "p50", "p90", "p99", "p99.9", "max")
+}
+
+// makeRowsSelectedLatencies returns a row per named latency percentile of all the schemes.
+func (p *TablePrinter) makeRowsSelectedLatencies(title string, schemes []string, h map[string]*Histogram, names ...string) [][]string {
+	summaries := make(map[string]LatencySummary, len(h))
+	for name, hist := range h {
+		summaries[name] = hist.Summary()
+	}
+
+	percentiles := []struct {
+		name  string
+		value func(s LatencySummary) time.Duration
+	}{
+		{"p50", func(s LatencySummary) time.Duration { return s.P50 }},
+		{"p90", func(s LatencySummary) time.Duration { return s.P90 }},
+		{"p99", func(s LatencySummary) time.Duration { return s.P99 }},
+		{"p99.9", func(s LatencySummary) time.Duration { return s.P999 }},
+		{"max", func(s LatencySummary) time.Duration { return s.Max }},
+	}
+
+	var rows [][]string
+	for _, pct := range percentiles {
+		if !contains(names, pct.name) {
+			continue
+		}
+		d := make(map[string][]time.Duration, len(summaries))
+		for name, s := range summaries {
+			d[name] = []time.Duration{pct.value(s)}
+		}
+		rows = append(rows, append(
+			[]string{fmt.Sprintf("%s, latency %s", title, pct.name)},
+			p.makeRowDataDurations(schemes, d, time.Microsecond)...,
+		))
+	}
+	return rows
+}
+
+func (p *TablePrinter) makeRowData(schemes []string, samples map[string][]float64, format func(float64) string) []string {
+	return p.makeRowDataDiff(schemes, samples, format, calcDiffPercent)
+}
+
+// makeRowDataDiff is makeRowData calculating the differences with the given function,
+// which is calcRateDiffPercent for the rates as higher rates are the better ones.
+func (p *TablePrinter) makeRowDataDiff(schemes []string, samples map[string][]float64, format func(float64) string,
+	diffPercent func(baseline, newVal int64) float64) []string {
+	var row []string
+	for _, name := range schemes {
+		row = append(row, p.formatStats(newStats(samples[name]), format))
+	}
+
+	baseline := samples[schemes[0]]
+	baselineMean := int64(newStats(baseline).Mean)
+	for _, name := range schemes[1:] {
+		if baselineMean == 0 {
+			// counts such as write conflicts are often zero, there is nothing to compare with then
+			row = append(row, "n/a")
+			continue
+		}
+		diff := fmt.Sprintf("%.2f%%", diffPercent(baselineMean, int64(newStats(samples[name]).Mean)))
+		if significantDiff(baseline, samples[name]) {
+			diff += " " + significanceMarker
+		}
+		row = append(row, diff)
+	}
+	return row
+}
+
+// formatStats formats a single measurement as is and repeated ones as
+// "mean ±CI95 [min..max] σ=stddev".
+func (p *TablePrinter) formatStats(st Stats, format func(float64) string) string {
+	if st.N <= 1 {
+		return format(st.Mean)
+	}
+	return fmt.Sprintf("%s ±%s [%s..%s] σ=%s",
+		format(st.Mean), format(st.CI95), format(st.Min), format(st.Max), format(st.StdDev))
+}
+
+// formatWidth formats a duration omitting its zero trailing units, e.g. 24h0m0s as 24h.
+func formatWidth(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// formatCount formats round document counts in a short form, e.g. 10000 as 10k.
+func formatCount(n int) string {
+	switch {
+	case n >= 1000000 && n%1000000 == 0:
+		return fmt.Sprintf("%dM", n/1000000)
+	case n >= 1000 && n%1000 == 0:
+		return fmt.Sprintf("%dk", n/1000)
+	default:
+		return fmt.Sprintf("%d", n)
 	}
 }
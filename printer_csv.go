@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CSVPrinter writes a row per measurement, with the run metadata repeated in every row
+// so that the rows of several runs can be concatenated and told apart.
+type CSVPrinter struct{}
+
+func (p *CSVPrinter) Print(w io.Writer, r *TesterResults) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{
+		"started_at", "go_version", "mongo_version", "host",
+		"scenario", "case", "metric", "scheme", "trial", "value", "unit",
+	}
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	startedAt := r.Metadata.StartedAt.Format(time.RFC3339)
+	for _, m := range r.Measurements() {
+		record := []string{
+			startedAt, r.Metadata.GoVersion, r.Metadata.MongoVersion, r.Metadata.Host,
+			m.Scenario, m.Case, m.Metric, m.Scheme,
+			strconv.Itoa(m.Trial),
+			strconv.FormatFloat(m.Value, 'f', -1, 64),
+			m.Unit,
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// chartPalette holds the colors of the schemes in the charts, it is reused cyclically.
+var chartPalette = []string{
+	"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7",
+	"#9c755f", "#bab0ac", "#1f77b4", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#17becf",
+}
+
+// HTMLPrinter writes a self-contained HTML report with the charts rendered as inline SVG,
+// so the file can be viewed offline.
+type HTMLPrinter struct{}
+
+type htmlReport struct {
+	Metadata    RunMetadata
+	Schemes     []string
+	Repetitions int
+	Config      string
+	Sections    []htmlSection
+}
+
+type htmlSection struct {
+	Title  string
+	Charts []template.HTML
+}
+
+func (p *HTMLPrinter) Print(w io.Writer, r *TesterResults) error {
+	config, err := json.MarshalIndent(r.Metadata.Config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize config: %w", err)
+	}
+
+	report := htmlReport{
+		Metadata:    r.Metadata,
+		Schemes:     r.Schemes,
+		Repetitions: r.Repetitions,
+		Config:      string(config),
+		Sections:    p.sections(r),
+	}
+	return htmlReportTemplate.Execute(w, report)
+}
+
+// sections returns a section per scenario with a bar chart per measured metric
+// followed by the throughput charts.
+func (p *HTMLPrinter) sections(r *TesterResults) []htmlSection {
+	colors := make(map[string]string, len(r.Schemes))
+	for i, name := range r.Schemes {
+		colors[name] = chartPalette[i%len(chartPalette)]
+	}
+
+	means, order := meanMeasurements(r.Measurements())
+
+	var sections []htmlSection
+	sectionIdx := make(map[string]int)
+	charted := make(map[string]bool)
+	for _, key := range order {
+		m := means[key]
+		si, ok := sectionIdx[m.Scenario]
+		if !ok {
+			si = len(sections)
+			sectionIdx[m.Scenario] = si
+			sections = append(sections, htmlSection{Title: m.Scenario})
+		}
+
+		chartKey := m.Scenario + "|" + m.Case + "|" + m.Metric
+		if charted[chartKey] {
+			continue
+		}
+		charted[chartKey] = true
+
+		var bars []chartBar
+		for _, name := range r.Schemes {
+			m.Scheme = name
+			if v, ok := means[m.Key()]; ok {
+				bars = append(bars, chartBar{Label: name, Value: v.Value, Color: colors[name]})
+			}
+		}
+		sections[si].Charts = append(sections[si].Charts, barChartSVG(fmt.Sprintf("%s, %s", m.Metric, m.Case), bars, m.Unit))
+	}
+
+	for i := range sections {
+		sections[i].Charts = append(sections[i].Charts, p.throughputCharts(r, sections[i].Title, colors)...)
+	}
+	return sections
+}
+
+// throughputCharts returns the line charts of the insert throughput depending on the batch size
+// and on the number of documents in the collection.
+func (p *HTMLPrinter) throughputCharts(r *TesterResults, scenario string, colors map[string]string) []template.HTML {
+	var points []throughputPoint
+	var charts []template.HTML
+	switch scenario {
+	case scenarioInsertBatches:
+		for _, res := range r.InsertsBatched {
+			points = append(points, throughputPoint{res.BatchSize, res.TotalDocs, res.Durations})
+			charts = append(charts, p.timelineChart(fmt.Sprintf(
+				"Batch throughput, %s docs, batch size = %s", formatCount(res.TotalDocs), formatCount(res.BatchSize),
+			), r.Schemes, res.Timelines, colors))
+		}
+	case scenarioInsertBatchesWithPresent:
+		for _, res := range r.InsertsBatchedWithPresent {
+			points = append(points, throughputPoint{res.BatchSize, res.InsertCount, res.InsertDurations})
+			charts = append(charts, p.timelineChart(fmt.Sprintf(
+				"Batch throughput, %s docs inserted after %s present, batch size = %s",
+				formatCount(res.InsertCount), formatCount(res.PresentCount), formatCount(res.BatchSize),
+			), r.Schemes, res.Timelines, colors))
+		}
+	}
+	if len(points) < 2 {
+		return charts
+	}
+
+	var series []chartSeries
+	for _, name := range r.Schemes {
+		s := chartSeries{Label: name, Color: colors[name]}
+		for _, pt := range points {
+			mean := newStats(durationSamples(pt.durations[name])).Mean
+			if mean == 0 {
+				continue
+			}
+			s.X = append(s.X, float64(pt.batchSize))
+			s.Y = append(s.Y, float64(pt.docs)/time.Duration(mean).Seconds())
+		}
+		series = append(series, s)
+	}
+	return append([]template.HTML{lineChartSVG("Insert throughput by batch size", "batch size", "docs/s", series)}, charts...)
+}
+
+// timelineChartPoints is the maximum number of points per line of the timeline charts,
+// the batches are averaged into buckets to keep the charts light.
+const timelineChartPoints = 200
+
+// timelineChart renders the throughput of the batches of the first trial
+// depending on the number of documents in the collection.
+func (p *HTMLPrinter) timelineChart(title string, schemes []string, timelines map[string][]*BatchTimeline, colors map[string]string) template.HTML {
+	var series []chartSeries
+	for _, name := range schemes {
+		if len(timelines[name]) == 0 {
+			continue
+		}
+		samples := timelines[name][0].Samples
+		bucket := (len(samples) + timelineChartPoints - 1) / timelineChartPoints
+
+		s := chartSeries{Label: name, Color: colors[name]}
+		for i := 0; i < len(samples); i += bucket {
+			end := i + bucket
+			if end > len(samples) {
+				end = len(samples)
+			}
+			var docs int
+			var d time.Duration
+			for _, sample := range samples[i:end] {
+				docs += sample.Docs
+				d += sample.Duration
+			}
+			if d <= 0 {
+				continue
+			}
+			s.X = append(s.X, float64(samples[end-1].CollectionDocs))
+			s.Y = append(s.Y, float64(docs)/d.Seconds())
+		}
+		series = append(series, s)
+	}
+	return lineChartSVG(title, "documents in the collection", "docs/s", series)
+}
+
+type throughputPoint struct {
+	batchSize int
+	docs      int
+	durations map[string][]time.Duration
+}
+
+type chartBar struct {
+	Label string
+	Value float64
+	Color string
+}
+
+type chartSeries struct {
+	Label string
+	Color string
+	X     []float64
+	Y     []float64
+}
+
+const (
+	chartWidth      = 720
+	chartLabelWidth = 140
+	chartValueWidth = 90
+	chartBarHeight  = 20
+	chartBarGap     = 6
+	chartTitleSpace = 28
+)
+
+// barChartSVG renders a horizontal bar chart with a bar per scheme, the bars of negative values
+// extend to the left of the zero axis.
+func barChartSVG(title string, bars []chartBar, unit string) template.HTML {
+	var maxPos, maxNeg float64
+	for _, b := range bars {
+		if math.IsNaN(b.Value) || math.IsInf(b.Value, 0) {
+			continue
+		}
+		maxPos = math.Max(maxPos, b.Value)
+		maxNeg = math.Max(maxNeg, -b.Value)
+	}
+	span := maxPos + maxNeg
+	if span == 0 {
+		span = 1
+	}
+
+	height := chartTitleSpace + len(bars)*(chartBarHeight+chartBarGap)
+	plotWidth := float64(chartWidth - chartLabelWidth - chartValueWidth)
+	zeroX := float64(chartLabelWidth) + maxNeg/span*plotWidth
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" class="chart">`, chartWidth, height)
+	fmt.Fprintf(&sb, `<text x="0" y="16" class="title">%s</text>`, html.EscapeString(title))
+	if maxNeg > 0 {
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" class="axis"/>`, zeroX, chartTitleSpace-2, zeroX, height)
+	}
+	for i, b := range bars {
+		y := chartTitleSpace + i*(chartBarHeight+chartBarGap)
+		var width float64
+		if !math.IsNaN(b.Value) && !math.IsInf(b.Value, 0) {
+			width = math.Abs(b.Value) / span * plotWidth
+		}
+		x := zeroX
+		if b.Value < 0 {
+			x -= width
+		}
+		fmt.Fprintf(&sb, `<text x="%d" y="%d" text-anchor="end">%s</text>`,
+			chartLabelWidth-8, y+chartBarHeight-5, html.EscapeString(b.Label))
+		fmt.Fprintf(&sb, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"/>`,
+			x, y, width, chartBarHeight, b.Color)
+		// the values of the negative bars are written to the right of the zero axis
+		valueX := zeroX + 6
+		if b.Value > 0 {
+			valueX += width
+		}
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%d">%s</text>`,
+			valueX, y+chartBarHeight-5, html.EscapeString(formatMeasurementValue(b.Value, unit)))
+	}
+	sb.WriteString(`</svg>`)
+	return template.HTML(sb.String())
+}
+
+// lineChartSVG renders a line chart with a line per series, the x axis is logarithmic
+// when the values span more than two orders of magnitude.
+func lineChartSVG(title, xLabel, yLabel string, series []chartSeries) template.HTML {
+	const (
+		height       = 360
+		left         = 80
+		right        = 160
+		top          = chartTitleSpace + 10
+		bottom       = 40
+		gridLines    = 5
+		legendHeight = 18
+	)
+
+	minX, maxX, maxY := math.Inf(1), math.Inf(-1), 0.0
+	for _, s := range series {
+		for i := range s.X {
+			minX = math.Min(minX, s.X[i])
+			maxX = math.Max(maxX, s.X[i])
+			maxY = math.Max(maxY, s.Y[i])
+		}
+	}
+	if math.IsInf(minX, 0) {
+		return ""
+	}
+	if maxY == 0 {
+		maxY = 1
+	}
+
+	logX := minX > 0 && maxX/minX > 100
+	scaleX := func(x float64) float64 {
+		if maxX == minX {
+			return left
+		}
+		if logX {
+			return left + (math.Log(x)-math.Log(minX))/(math.Log(maxX)-math.Log(minX))*(chartWidth-left-right)
+		}
+		return left + (x-minX)/(maxX-minX)*(chartWidth-left-right)
+	}
+	scaleY := func(y float64) float64 {
+		return top + (1-y/maxY)*(height-top-bottom)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" class="chart">`, chartWidth, height)
+	fmt.Fprintf(&sb, `<text x="0" y="16" class="title">%s</text>`, html.EscapeString(title))
+
+	for i := 0; i <= gridLines; i++ {
+		v := maxY * float64(i) / gridLines
+		y := scaleY(v)
+		fmt.Fprintf(&sb, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" class="grid"/>`, left, y, chartWidth-right, y)
+		fmt.Fprintf(&sb, `<text x="%d" y="%.1f" text-anchor="end">%s</text>`, left-6, y+4, formatAxisValue(v))
+	}
+	fmt.Fprintf(&sb, `<text x="%d" y="%d" text-anchor="middle">%s</text>`,
+		left+(chartWidth-left-right)/2, height-6, html.EscapeString(xLabel))
+	fmt.Fprintf(&sb, `<text x="0" y="%d">%s</text>`, top-12, html.EscapeString(yLabel))
+
+	// labelling every x value unless there are too many of them to fit
+	var xs []float64
+	seen := make(map[float64]bool)
+	for _, s := range series {
+		for _, x := range s.X {
+			if !seen[x] {
+				seen[x] = true
+				xs = append(xs, x)
+			}
+		}
+	}
+	if len(xs) > 10 {
+		xs = xs[:0]
+		for i := 0; i <= gridLines; i++ {
+			if logX {
+				xs = append(xs, minX*math.Pow(maxX/minX, float64(i)/gridLines))
+			} else {
+				xs = append(xs, minX+(maxX-minX)*float64(i)/gridLines)
+			}
+		}
+	}
+	for _, x := range xs {
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`,
+			scaleX(x), height-bottom+16, formatAxisValue(x))
+	}
+
+	for li, s := range series {
+		var pts []string
+		for i := range s.X {
+			x, y := scaleX(s.X[i]), scaleY(s.Y[i])
+			pts = append(pts, fmt.Sprintf("%.1f,%.1f", x, y))
+			if len(s.X) <= 20 {
+				fmt.Fprintf(&sb, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`, x, y, s.Color)
+			}
+		}
+		fmt.Fprintf(&sb, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`,
+			strings.Join(pts, " "), s.Color)
+
+		ly := top + li*legendHeight
+		fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/>`, chartWidth-right+16, ly, s.Color)
+		fmt.Fprintf(&sb, `<text x="%d" y="%d">%s</text>`, chartWidth-right+34, ly+11, html.EscapeString(s.Label))
+	}
+
+	sb.WriteString(`</svg>`)
+	return template.HTML(sb.String())
+}
+
+// formatAxisValue formats axis values in a short form, e.g. 25000 as 25k.
+func formatAxisValue(v float64) string {
+	switch {
+	case v >= 1e6:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", v/1e6), ".0") + "M"
+	case v >= 1e3:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", v/1e3), ".0") + "k"
+	default:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", v), ".0")
+	}
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Mongo ID performance report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1, h2 { font-weight: 600; }
+table.env td { padding: 2px 12px 2px 0; vertical-align: top; }
+pre { background: #f6f8fa; padding: 1em; overflow: auto; }
+svg.chart { display: block; margin: 1.5em 0; font-size: 12px; }
+svg.chart text { fill: #222; }
+svg.chart text.title { font-size: 14px; font-weight: 600; }
+svg.chart line.grid { stroke: #e5e5e5; }
+svg.chart line.axis { stroke: #888; }
+</style>
+</head>
+<body>
+<h1>Mongo ID performance report</h1>
+
+<h2>Environment</h2>
+<table class="env">
+<tr><td>Started at</td><td>{{.Metadata.StartedAt.Format "2006-01-02 15:04:05 MST"}}</td></tr>
+<tr><td>Duration</td><td>{{.Metadata.Duration}}</td></tr>
+<tr><td>Go version</td><td>{{.Metadata.GoVersion}}</td></tr>
+<tr><td>Mongo version</td><td>{{.Metadata.MongoVersion}}</td></tr>
+<tr><td>Platform</td><td>{{.Metadata.Platform}}</td></tr>
+<tr><td>Host</td><td>{{.Metadata.Host}}</td></tr>
+<tr><td>Schemes</td><td>{{range $i, $s := .Schemes}}{{if $i}}, {{end}}{{$s}}{{end}}</td></tr>
+<tr><td>Trials</td><td>{{.Repetitions}}</td></tr>
+</table>
+<details><summary>Config</summary><pre>{{.Config}}</pre></details>
+{{range .Sections}}
+<h2>{{.Title}}</h2>
+{{range .Charts}}{{.}}
+{{end}}{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var svgRectPattern = regexp.MustCompile(`<rect x="([^"]+)" y="\d+" width="([^"]+)"`)
+
+// barRects returns the x and the width of every bar of the chart.
+func barRects(t *testing.T, chart string) (xs, widths []float64) {
+	t.Helper()
+	for _, m := range svgRectPattern.FindAllStringSubmatch(chart, -1) {
+		x, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			t.Fatalf("invalid bar x %q", m[1])
+		}
+		w, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			t.Fatalf("invalid bar width %q", m[2])
+		}
+		xs, widths = append(xs, x), append(widths, w)
+	}
+	return xs, widths
+}
+
+func TestBarChartSVGNegativeValues(t *testing.T) {
+	chart := string(barChartSVG("diff", []chartBar{
+		{Label: "a", Value: 30},
+		{Label: "b", Value: -10},
+		{Label: "c", Value: 0},
+	}, "%"))
+	xs, widths := barRects(t, chart)
+	if len(xs) != 3 {
+		t.Fatalf("chart has %d bars, want 3:\n%s", len(xs), chart)
+	}
+
+	// the bars span the plot width, a quarter of it to the left of the zero axis
+	plotWidth := float64(chartWidth - chartLabelWidth - chartValueWidth)
+	zeroX := float64(chartLabelWidth) + plotWidth/4
+	want := []struct{ x, width float64 }{
+		{zeroX, plotWidth * 3 / 4},
+		{chartLabelWidth, plotWidth / 4},
+		{zeroX, 0},
+	}
+	for i, w := range want {
+		if xs[i] != w.x || widths[i] != w.width {
+			t.Errorf("bar %d at %v of width %v, want at %v of width %v", i, xs[i], widths[i], w.x, w.width)
+		}
+	}
+	if !strings.Contains(chart, `class="axis"`) {
+		t.Error("chart of negative values has no zero axis")
+	}
+}
+
+func TestBarChartSVGZeroValues(t *testing.T) {
+	chart := string(barChartSVG("zero", []chartBar{{Label: "a"}, {Label: "b"}}, "ns"))
+	if strings.Contains(chart, "NaN") || strings.Contains(chart, "Inf") {
+		t.Fatalf("chart of zero values holds NaN or Inf:\n%s", chart)
+	}
+	xs, widths := barRects(t, chart)
+	for i := range xs {
+		if xs[i] != chartLabelWidth || widths[i] != 0 {
+			t.Errorf("bar %d at %v of width %v, want an empty bar at %d", i, xs[i], widths[i], chartLabelWidth)
+		}
+	}
+	if strings.Contains(chart, `class="axis"`) {
+		t.Error("chart of non-negative values has a zero axis")
+	}
+}
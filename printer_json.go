@@ -0,0 +1,16 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONPrinter writes the results together with the run metadata as an indented JSON document.
+// Durations are written in nanoseconds and sizes in bytes.
+type JSONPrinter struct{}
+
+func (p *JSONPrinter) Print(w io.Writer, r *TesterResults) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
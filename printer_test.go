@@ -0,0 +1,13 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTablePrinterNoSchemes(t *testing.T) {
+	err := new(TablePrinter).Print(new(strings.Builder), &TesterResults{})
+	if err == nil || !strings.Contains(err.Error(), "no schemes") {
+		t.Fatalf("error = %v, want one about no schemes", err)
+	}
+}
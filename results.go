@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// RunMetadata describes the environment and the settings the results were obtained with.
+type RunMetadata struct {
+	StartedAt    time.Time     `json:"startedAt"`
+	Duration     time.Duration `json:"durationNs"`
+	GoVersion    string        `json:"goVersion"`
+	Platform     string        `json:"platform"`
+	Host         string        `json:"host"`
+	MongoVersion string        `json:"mongoVersion"`
+	Config       *Config       `json:"config"`
+}
+
+func (t *Tester) runMetadata() RunMetadata {
+	host, _ := os.Hostname()
+	return RunMetadata{
+		StartedAt:    time.Now(),
+		GoVersion:    runtime.Version(),
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+		Host:         host,
+		MongoVersion: t.serverVersion(),
+		Config:       t.Config,
+	}
+}
+
+// serverVersion returns the version of the mongo server, or "unknown" when it cannot be obtained.
+func (t *Tester) serverVersion() string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var info struct {
+		Version string `bson:"version"`
+	}
+	err := t.Coll.Database().RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&info)
+	if err != nil || info.Version == "" {
+		return "unknown"
+	}
+	return info.Version
+}
+
+// unitOpsPerSecond is the unit of the only measurements which higher values are the better ones.
+const unitOpsPerSecond = "ops/s"
+
+// Measurement is a single value of a metric obtained for a scheme in a test case.
+type Measurement struct {
+	// Scenario is the name of the scenario the measurement was obtained in.
+	Scenario string
+	// Case describes the parameters of the scenario run, e.g. "totalDocs=1000000 batchSize=1000".
+	Case string
+	// Metric is the name of the measured value, e.g. "duration" or "latencyP99".
+	Metric string
+	Scheme string
+	// Trial is the number of the trial the value was obtained in,
+	// it is -1 for the values aggregated over all the trials.
+	Trial int
+	Value float64
+	// Unit is either "ns", "bytes", "count" or "ops/s".
+	Unit string
+	// Informational measurements are reported without being compared with the baseline, as they vary
+	// with the state of the storage engine more than with the scheme, e.g. the compressed pages written.
+	Informational bool
+}
+
+// Key identifies the measured value regardless of the trial.
+func (m Measurement) Key() string {
+	return fmt.Sprintf("%s|%s|%s|%s", m.Scenario, m.Case, m.Metric, m.Scheme)
+}
+
+// Measurements flattens the results into a list of measurements.
+func (r *TesterResults) Measurements() []Measurement {
+	var result []Measurement
+
+	for _, res := range r.InsertsBatched {
+		c := fmt.Sprintf("totalDocs=%d batchSize=%d", res.TotalDocs, res.BatchSize)
+		result = append(result, durationMeasurements(scenarioInsertBatches, c, "duration", r.Schemes, res.Durations)...)
+		result = append(result, engineMeasurements(scenarioInsertBatches, c, r.Schemes, res.EngineStats)...)
+	}
+
+	if res := r.Inserts; res != nil {
+		c := fmt.Sprintf("totalDocs=%d", res.TotalDocs)
+		result = append(result, durationMeasurements(scenarioInserts, c, "duration", r.Schemes, res.Durations)...)
+		result = append(result, latencyMeasurements(scenarioInserts, c, "latency", r.Schemes, res.Latencies)...)
+		result = append(result, engineMeasurements(scenarioInserts, c, r.Schemes, res.EngineStats)...)
+	}
+
+	for _, res := range r.InsertsBatchedWithPresent {
+		c := fmt.Sprintf("insertCount=%d presentCount=%d batchSize=%d", res.InsertCount, res.PresentCount, res.BatchSize)
+		result = append(result, durationMeasurements(scenarioInsertBatchesWithPresent, c, "insertDuration", r.Schemes, res.InsertDurations)...)
+		result = append(result, sizeMeasurements(scenarioInsertBatchesWithPresent, c, "idIndexSize", r.Schemes, res.IdxSizes)...)
+		result = append(result, collStatsMeasurements(scenarioInsertBatchesWithPresent, c, r.Schemes, res.CollStats)...)
+		result = append(result, engineMeasurements(scenarioInsertBatchesWithPresent, c, r.Schemes, res.EngineStats)...)
+		for _, index := range collStatsIndexNames(res.CollStats)[1:] {
+			ic := fmt.Sprintf("%s index=%s", c, index)
+			result = append(result, sizeMeasurements(scenarioInsertBatchesWithPresent, ic, "indexSize", r.Schemes, collStatsIndexSizes(res.CollStats, index))...)
+		}
+		for _, get := range res.Gets {
+			gc := fmt.Sprintf("%s keys=%s", c, get.Distribution)
+			result = append(result, durationMeasurements(scenarioInsertBatchesWithPresent, gc, "getAvgDuration", r.Schemes, get.Durations)...)
+			result = append(result, latencyMeasurements(scenarioInsertBatchesWithPresent, gc, "getLatency", r.Schemes, get.Latencies)...)
+		}
+		for _, lk := range res.Lookups {
+			lc := fmt.Sprintf("%s lookupSize=%d", c, lk.Size)
+			result = append(result, durationMeasurements(scenarioInsertBatchesWithPresent, lc, "lookupAvgDuration", r.Schemes, lk.Durations)...)
+			result = append(result, latencyMeasurements(scenarioInsertBatchesWithPresent, lc, "lookupLatency", r.Schemes, lk.Latencies)...)
+		}
+		for _, pg := range res.Pagination {
+			pc := fmt.Sprintf("%s pageSize=%d", c, pg.PageSize)
+			result = append(result, durationMeasurements(scenarioInsertBatchesWithPresent, pc, "walkDuration", r.Schemes, pg.WalkDurations)...)
+			result = append(result, latencyMeasurements(scenarioInsertBatchesWithPresent, pc, "pageLatency", r.Schemes, pg.PageLatencies)...)
+		}
+		for _, rq := range res.RangeQueries {
+			rc := fmt.Sprintf("%s rangeWidth=%s", c, formatWidth(rq.Width))
+			result = append(result, latencyMeasurements(scenarioInsertBatchesWithPresent, rc, "rangeLatency", r.Schemes, rq.Latencies)...)
+			result = append(result, countMeasurements(scenarioInsertBatchesWithPresent, rc, "rangeKeysExamined", r.Schemes, rq.KeysExamined)...)
+		}
+	}
+
+	if res := r.UpdatesDeletes; res != nil {
+		for _, op := range res.Operations {
+			c := fmt.Sprintf("presentCount=%d recentCount=%d ops=%d deleteManySize=%d selection=%s",
+				res.PresentCount, res.RecentCount, res.Ops, res.DeleteManySize, op.Selection)
+			result = append(result, durationMeasurements(scenarioUpdatesDeletes, c, op.Operation+"AvgDuration", r.Schemes, op.Durations)...)
+			result = append(result, latencyMeasurements(scenarioUpdatesDeletes, c, op.Operation+"Latency", r.Schemes, op.Latencies)...)
+		}
+		c := fmt.Sprintf("presentCount=%d recentCount=%d ops=%d deleteManySize=%d",
+			res.PresentCount, res.RecentCount, res.Ops, res.DeleteManySize)
+		result = append(result, engineMeasurements(scenarioUpdatesDeletes, c, r.Schemes, res.EngineStats)...)
+	}
+
+	for _, res := range r.MixedWorkloads {
+		c := fmt.Sprintf("presentCount=%d duration=%s workers=%d distribution=%s mix=%s",
+			res.PresentCount, formatWidth(res.Duration), res.Workers, res.Distribution, res.Mix)
+		result = append(result, rateMeasurements(scenarioMixedWorkload, c, "throughput", r.Schemes, res.Throughputs)...)
+		for _, op := range allWorkloadOperations {
+			opRes, ok := res.Operations[op]
+			if !ok || opRes == nil {
+				continue
+			}
+			result = append(result, rateMeasurements(scenarioMixedWorkload, c, op+"Throughput", r.Schemes, opRes.Throughputs)...)
+			result = append(result, latencyMeasurements(scenarioMixedWorkload, c, op+"Latency", r.Schemes, opRes.Latencies)...)
+		}
+		result = append(result, engineMeasurements(scenarioMixedWorkload, c, r.Schemes, res.EngineStats)...)
+	}
+
+	if res := r.SecondaryIndexes; res != nil {
+		c := fmt.Sprintf("totalDocs=%d batchSize=%d tenants=%d", res.TotalDocs, res.BatchSize, res.Tenants)
+		result = append(result, durationMeasurements(scenarioSecondaryIndexes, c, "duration", r.Schemes, res.Durations)...)
+		result = append(result, durationMeasurements(scenarioSecondaryIndexes, c, "indexedDuration", r.Schemes, res.IndexedDurations)...)
+		result = append(result, durationMeasurements(scenarioSecondaryIndexes, c, "indexOverhead", r.Schemes, res.Overheads)...)
+		for _, idx := range res.Indexes {
+			ic := fmt.Sprintf("%s index=%s", c, idx.Keys)
+			result = append(result, sizeMeasurements(scenarioSecondaryIndexes, ic, "indexSize", r.Schemes, idx.Sizes)...)
+		}
+		result = append(result, collStatsMeasurements(scenarioSecondaryIndexes, c, r.Schemes, res.CollStats)...)
+		result = append(result, engineMeasurements(scenarioSecondaryIndexes, c, r.Schemes, res.EngineStats)...)
+	}
+
+	for _, res := range r.ConcurrentInserts {
+		c := fmt.Sprintf("totalDocs=%d batchSize=%d workers=%d generator=%s", res.TotalDocs, res.BatchSize, res.Workers, res.Generator)
+		result = append(result, durationMeasurements(scenarioConcurrentInserts, c, "duration", r.Schemes, res.Durations)...)
+		result = append(result, countMeasurements(scenarioConcurrentInserts, c, "writeConflicts", r.Schemes, res.WriteConflicts)...)
+		result = append(result, countMeasurements(scenarioConcurrentInserts, c, "retries", r.Schemes, res.Retries)...)
+		result = append(result, engineMeasurements(scenarioConcurrentInserts, c, r.Schemes, res.EngineStats)...)
+	}
+
+	return result
+}
+
+// engineMeasurements returns the changes of the WiredTiger counters of every phase as informational measurements,
+// the counters of the whole server have their metric names prefixed with "server".
+func engineMeasurements(scenario, c string, schemes []string, stats []*PhaseEngineStats) []Measurement {
+	var result []Measurement
+	for _, ps := range stats {
+		pc := fmt.Sprintf("%s phase=%s", c, ps.Phase)
+		for _, f := range engineStatsFields {
+			unit := "count"
+			if f.bytes {
+				unit = "bytes"
+			}
+			serverMetric := "server" + strings.ToUpper(f.metric[:1]) + f.metric[1:]
+			result = append(result, int64Measurements(scenario, pc, f.metric, unit, schemes, engineStatsValues(ps.Collection, f.value))...)
+			result = append(result, int64Measurements(scenario, pc, serverMetric, unit, schemes, engineStatsValues(ps.Server, f.value))...)
+		}
+	}
+	return informational(result)
+}
+
+// collStatsMeasurements returns the collection sizes and compression counters of the stats.
+func collStatsMeasurements(scenario, c string, schemes []string, stats map[string][]*CollectionStats) []Measurement {
+	var result []Measurement
+	for _, f := range collStatsSizeFields {
+		result = append(result, sizeMeasurements(scenario, c, f.metric, schemes, collStatsValues(stats, f.value))...)
+	}
+	for _, f := range collStatsCountFields {
+		result = append(result, informational(countMeasurements(scenario, c, f.metric, schemes, collStatsValues(stats, f.value)))...)
+	}
+	return result
+}
+
+// informational marks the measurements as informational ones.
+func informational(measurements []Measurement) []Measurement {
+	for i := range measurements {
+		measurements[i].Informational = true
+	}
+	return measurements
+}
+
+func durationMeasurements(scenario, c, metric string, schemes []string, d map[string][]time.Duration) []Measurement {
+	var result []Measurement
+	for _, scheme := range schemes {
+		for trial, v := range d[scheme] {
+			result = append(result, Measurement{
+				Scenario: scenario, Case: c, Metric: metric, Scheme: scheme, Trial: trial, Value: float64(v), Unit: "ns",
+			})
+		}
+	}
+	return result
+}
+
+func sizeMeasurements(scenario, c, metric string, schemes []string, s map[string][]int64) []Measurement {
+	return int64Measurements(scenario, c, metric, "bytes", schemes, s)
+}
+
+func countMeasurements(scenario, c, metric string, schemes []string, n map[string][]int64) []Measurement {
+	return int64Measurements(scenario, c, metric, "count", schemes, n)
+}
+
+func int64Measurements(scenario, c, metric, unit string, schemes []string, values map[string][]int64) []Measurement {
+	var result []Measurement
+	for _, scheme := range schemes {
+		for trial, v := range values[scheme] {
+			result = append(result, Measurement{
+				Scenario: scenario, Case: c, Metric: metric, Scheme: scheme, Trial: trial, Value: float64(v), Unit: unit,
+			})
+		}
+	}
+	return result
+}
+
+func rateMeasurements(scenario, c, metric string, schemes []string, rates map[string][]float64) []Measurement {
+	var result []Measurement
+	for _, scheme := range schemes {
+		for trial, v := range rates[scheme] {
+			result = append(result, Measurement{
+				Scenario: scenario, Case: c, Metric: metric, Scheme: scheme, Trial: trial, Value: v, Unit: unitOpsPerSecond,
+			})
+		}
+	}
+	return result
+}
+
+// latencyMeasurements returns the percentiles of the latencies with metric as the prefix of their names.
+func latencyMeasurements(scenario, c, metric string, schemes []string, h map[string]*Histogram) []Measurement {
+	var result []Measurement
+	for _, scheme := range schemes {
+		hist, ok := h[scheme]
+		if !ok {
+			continue
+		}
+		s := hist.Summary()
+		for _, v := range []struct {
+			name  string
+			value time.Duration
+		}{
+			{"P50", s.P50}, {"P90", s.P90}, {"P99", s.P99}, {"P999", s.P999}, {"Max", s.Max},
+		} {
+			result = append(result, Measurement{
+				Scenario: scenario, Case: c, Metric: metric + v.name, Scheme: scheme, Trial: -1, Value: float64(v.value), Unit: "ns",
+			})
+		}
+	}
+	return result
+}
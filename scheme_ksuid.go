@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+func init() {
+	registerScheme(new(ksuidScheme))
+}
+
+type mongoDocumentKSUID struct {
+	ID      ksuid.KSUID `bson:"_id"`
+	Payload Payload     `bson:",inline"`
+}
+
+// ksuidScheme stores 20-byte KSUIDs as generic binary values.
+type ksuidScheme struct{}
+
+func (s *ksuidScheme) Name() string {
+	return "KSUID"
+}
+
+func (s *ksuidScheme) NewID() interface{} {
+	return ksuid.New()
+}
+
+func (s *ksuidScheme) NewIDAt(ts time.Time) interface{} {
+	id, err := ksuid.NewRandomWithTime(ts)
+	if err != nil {
+		panic(fmt.Errorf("failed to generate KSUID: %w", err))
+	}
+	return id
+}
+
+// MinIDAt returns the KSUID having the timestamp of the given second and a zero payload.
+func (s *ksuidScheme) MinIDAt(ts time.Time) interface{} {
+	id, err := ksuid.FromParts(ts, make([]byte, ksuidPayloadLen))
+	if err != nil {
+		panic(fmt.Errorf("failed to make KSUID: %w", err))
+	}
+	return id
+}
+
+func (s *ksuidScheme) NewDocument(id interface{}, payload Payload) interface{} {
+	return mongoDocumentKSUID{ID: id.(ksuid.KSUID), Payload: payload}
+}
+
+func (s *ksuidScheme) DocumentID(doc interface{}) interface{} {
+	return doc.(mongoDocumentKSUID).ID
+}
+
+func (s *ksuidScheme) DocumentType() reflect.Type {
+	return reflect.TypeOf(mongoDocumentKSUID{})
+}
+
+func (s *ksuidScheme) RegisterCodecs(rb *bsoncodec.RegistryBuilder) {
+	rb.RegisterTypeEncoder(ksuidType, bsoncodec.ValueEncoderFunc(KSUIDEncodeValue)).
+		RegisterTypeDecoder(ksuidType, bsoncodec.ValueDecoderFunc(KSUIDDecodeValue))
+}
+
+const ksuidPayloadLen = 16
+
+var ksuidType = reflect.TypeOf(ksuid.KSUID{})
+
+func KSUIDEncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != ksuidType {
+		return bsoncodec.ValueEncoderError{Name: "KSUIDEncodeValue", Types: []reflect.Type{ksuidType}, Received: val}
+	}
+	b, ok := val.Interface().(ksuid.KSUID)
+	if !ok {
+		return fmt.Errorf("failed to convert interface of type %s to %s",
+			reflect.TypeOf(val.Interface()).String(), reflect.TypeOf(b))
+	}
+
+	if err := vw.WriteBinaryWithSubtype(b[:], bsontype.BinaryGeneric); err != nil {
+		return fmt.Errorf("failed to write binary: %w", err)
+	}
+	return nil
+}
+
+func KSUIDDecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != ksuidType {
+		return bsoncodec.ValueDecoderError{Name: "KSUIDDecodeValue", Types: []reflect.Type{ksuidType}, Received: val}
+	}
+
+	var data []byte
+	var subtype byte
+	var err error
+
+	//nolint:exhaustive // the rest of types are covered by the `default` branch
+	switch vrType := vr.Type(); vrType {
+	case bsontype.Binary:
+		data, subtype, err = vr.ReadBinary()
+		if subtype != bsontype.BinaryGeneric {
+			return fmt.Errorf("unsupported binary subtype %v for KSUID", subtype)
+		}
+	case bsontype.Null:
+		err = vr.ReadNull()
+	case bsontype.Undefined:
+		err = vr.ReadUndefined()
+	default:
+		return fmt.Errorf("cannot decode %v into a KSUID", vrType)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read KSUID value: %w", err)
+	}
+	id, err := ksuid.FromBytes(data)
+	if err != nil {
+		return fmt.Errorf("failed to read KSUID from bytes: %w", err)
+	}
+	val.Set(reflect.ValueOf(id))
+	return nil
+}
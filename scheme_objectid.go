@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/binary"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func init() {
+	registerScheme(new(objectIDScheme))
+}
+
+type mongoDocumentObjectID struct {
+	ID      primitive.ObjectID `bson:"_id"`
+	Payload Payload            `bson:",inline"`
+}
+
+// objectIDScheme uses the native mongo ObjectID, which the driver encodes out of the box.
+type objectIDScheme struct{}
+
+func (s *objectIDScheme) Name() string {
+	return "ObjectId"
+}
+
+func (s *objectIDScheme) NewID() interface{} {
+	return primitive.NewObjectID()
+}
+
+func (s *objectIDScheme) NewIDAt(ts time.Time) interface{} {
+	return primitive.NewObjectIDFromTimestamp(ts)
+}
+
+// MinIDAt returns the ObjectID having the timestamp of the given second and zero in the rest of the bytes.
+func (s *objectIDScheme) MinIDAt(ts time.Time) interface{} {
+	return minObjectIDAt(ts)
+}
+
+func minObjectIDAt(ts time.Time) primitive.ObjectID {
+	var id primitive.ObjectID
+	binary.BigEndian.PutUint32(id[0:4], uint32(ts.Unix()))
+	return id
+}
+
+func (s *objectIDScheme) NewDocument(id interface{}, payload Payload) interface{} {
+	return mongoDocumentObjectID{ID: id.(primitive.ObjectID), Payload: payload}
+}
+
+func (s *objectIDScheme) DocumentID(doc interface{}) interface{} {
+	return doc.(mongoDocumentObjectID).ID
+}
+
+func (s *objectIDScheme) DocumentType() reflect.Type {
+	return reflect.TypeOf(mongoDocumentObjectID{})
+}
+
+func (s *objectIDScheme) RegisterCodecs(_ *bsoncodec.RegistryBuilder) {}
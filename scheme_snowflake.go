@@ -0,0 +1,155 @@
+package main
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+)
+
+func init() {
+	registerScheme(&snowflakeScheme{
+		gen: newSnowflakeGenerator(1),
+	})
+}
+
+type mongoDocumentSnowflake struct {
+	ID      int64   `bson:"_id"`
+	Payload Payload `bson:",inline"`
+}
+
+// snowflakeScheme stores Twitter Snowflake-style identifiers as BSON int64 values,
+// which the driver encodes out of the box.
+type snowflakeScheme struct {
+	gen *snowflakeGenerator
+}
+
+func (s *snowflakeScheme) Name() string {
+	return "Snowflake"
+}
+
+func (s *snowflakeScheme) NewID() interface{} {
+	return s.gen.Next()
+}
+
+func (s *snowflakeScheme) NewIDAt(ts time.Time) interface{} {
+	return s.gen.NextAt(ts)
+}
+
+// MinIDAt returns the identifier of the given millisecond having zero node and sequence numbers.
+func (s *snowflakeScheme) MinIDAt(ts time.Time) interface{} {
+	return snowflakeID(ts.UnixMilli(), 0, 0)
+}
+
+// ForWorker returns the scheme with a generator of its own, workers are given
+// distinct node numbers so that their identifiers never collide.
+func (s *snowflakeScheme) ForWorker(worker int) IDScheme {
+	return &snowflakeScheme{
+		gen: newSnowflakeGenerator(int64(worker%snowflakeMaxNode) + 1),
+	}
+}
+
+func (s *snowflakeScheme) NewDocument(id interface{}, payload Payload) interface{} {
+	return mongoDocumentSnowflake{ID: id.(int64), Payload: payload}
+}
+
+func (s *snowflakeScheme) DocumentID(doc interface{}) interface{} {
+	return doc.(mongoDocumentSnowflake).ID
+}
+
+func (s *snowflakeScheme) DocumentType() reflect.Type {
+	return reflect.TypeOf(mongoDocumentSnowflake{})
+}
+
+func (s *snowflakeScheme) RegisterCodecs(_ *bsoncodec.RegistryBuilder) {}
+
+const (
+	snowflakeEpoch        = 1288834974657 // Twitter epoch, in milliseconds
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	snowflakeMaxNode      = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSequence  = 1<<snowflakeSequenceBits - 1
+)
+
+// snowflakeGenerator generates 64-bit identifiers made of a 41-bit millisecond
+// timestamp, a 10-bit node number and a 12-bit per-millisecond sequence.
+type snowflakeGenerator struct {
+	mu       sync.Mutex
+	node     int64
+	lastMs   int64
+	sequence int64
+
+	// the state of NextAt is kept apart from the one of Next, as the times given to NextAt
+	// have nothing to do with the clock, e.g. they lie in the past
+	atRequestedMs int64
+	atLastMs      int64
+	atSequence    int64
+}
+
+func newSnowflakeGenerator(node int64) *snowflakeGenerator {
+	return &snowflakeGenerator{node: node & snowflakeMaxNode}
+}
+
+// Next returns the next identifier, waiting for the next millisecond
+// when the sequence of the current one is exhausted.
+func (g *snowflakeGenerator) Next() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now().UnixMilli()
+	if now < g.lastMs {
+		// the clock went backwards, keep on using the last known millisecond
+		now = g.lastMs
+	}
+
+	if now == g.lastMs {
+		g.sequence = (g.sequence + 1) & snowflakeMaxSequence
+		if g.sequence == 0 {
+			for now <= g.lastMs {
+				time.Sleep(100 * time.Microsecond)
+				now = time.Now().UnixMilli()
+			}
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastMs = now
+
+	return snowflakeID(now, g.node, g.sequence)
+}
+
+// NextAt returns the next identifier of the given time, moving on to the next
+// millisecond when the sequence of the current one is exhausted.
+// The identifiers are unique as long as the times do not go backwards, a time earlier
+// than the previous one starts a new series, e.g. the fixtures of another trial.
+func (g *snowflakeGenerator) NextAt(ts time.Time) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	requested := ts.UnixMilli()
+	ms := requested
+	if requested >= g.atRequestedMs && ms < g.atLastMs {
+		// the sequence of an earlier millisecond overflowed into this one
+		ms = g.atLastMs
+	}
+	g.atRequestedMs = requested
+
+	if ms == g.atLastMs {
+		g.atSequence = (g.atSequence + 1) & snowflakeMaxSequence
+		if g.atSequence == 0 {
+			ms++
+		}
+	} else {
+		g.atSequence = 0
+	}
+	g.atLastMs = ms
+
+	return snowflakeID(ms, g.node, g.atSequence)
+}
+
+func snowflakeID(ms, node, sequence int64) int64 {
+	return (ms-snowflakeEpoch)<<(snowflakeNodeBits+snowflakeSequenceBits) |
+		node<<snowflakeSequenceBits |
+		sequence
+}
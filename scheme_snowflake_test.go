@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnowflakeTimedDocsWithinTheirMillisecond(t *testing.T) {
+	s := &snowflakeScheme{gen: newSnowflakeGenerator(1)}
+	// the shared generator has issued identifiers of the current time before the fixtures are made
+	for i := 0; i < 10; i++ {
+		s.NewID()
+	}
+
+	end := time.Now()
+	for trial := 0; trial < 2; trial++ {
+		for i, doc := range generateTimedDocs(s, 1000, end, 30*24*time.Hour, nil) {
+			d := doc.(timedDocument)
+			id := d.ID.(int64)
+			from := s.MinIDAt(d.CreatedAt).(int64)
+			to := s.MinIDAt(d.CreatedAt.Add(time.Millisecond)).(int64)
+			if id < from || id >= to {
+				t.Fatalf("trial %d: ID %d of document %d created at %s is out of [%d, %d)", trial, id, i, d.CreatedAt, from, to)
+			}
+		}
+		end = end.Add(time.Second)
+	}
+}
+
+func TestSnowflakeNextAtUnique(t *testing.T) {
+	g := newSnowflakeGenerator(1)
+	ts := time.Now().Add(-time.Hour)
+
+	seen := make(map[int64]bool)
+	var last int64
+	// exhausting the sequence of a millisecond carries the identifiers over into the next one
+	for i := 0; i < 3*(snowflakeMaxSequence+1); i++ {
+		at := ts
+		if i >= 2*(snowflakeMaxSequence+1) {
+			at = ts.Add(time.Millisecond)
+		}
+		id := g.NextAt(at)
+		if seen[id] {
+			t.Fatalf("duplicate ID %d at %d", id, i)
+		}
+		if id <= last {
+			t.Fatalf("ID %d at %d is not greater than the previous one %d", id, i, last)
+		}
+		seen[id] = true
+		last = id
+	}
+}
+
+func TestSnowflakeNextMonotonic(t *testing.T) {
+	g := newSnowflakeGenerator(1)
+	g.NextAt(time.Now().Add(time.Hour))
+
+	var last int64
+	for i := 0; i < 2*(snowflakeMaxSequence+1); i++ {
+		id := g.Next()
+		if id <= last {
+			t.Fatalf("ID %d at %d is not greater than the previous one %d", id, i, last)
+		}
+		last = id
+	}
+	if ms := last>>(snowflakeNodeBits+snowflakeSequenceBits) + snowflakeEpoch; ms > time.Now().UnixMilli() {
+		t.Fatalf("ID of %d ms is ahead of the clock", ms)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func init() {
+	registerScheme(&timeOrderedStringScheme{
+		stringScheme: stringScheme{
+			name: "ULID-str",
+			gen:  func() string { return ulid.Make().String() },
+		},
+		genAt: func(ts time.Time) string { return ulid.MustNew(ulid.Timestamp(ts), ulid.DefaultEntropy()).String() },
+		minAt: func(ts time.Time) string { return minULIDAt(ts).String() },
+	})
+	registerScheme(&stringScheme{
+		name: "UUIDv4-str",
+		gen:  func() string { return uuid.New().String() },
+	})
+	registerScheme(&timeOrderedStringScheme{
+		stringScheme: stringScheme{
+			name: "ObjectId-hex",
+			gen:  func() string { return primitive.NewObjectID().Hex() },
+		},
+		genAt: func(ts time.Time) string { return primitive.NewObjectIDFromTimestamp(ts).Hex() },
+		minAt: func(ts time.Time) string { return minObjectIDAt(ts).Hex() },
+	})
+}
+
+type mongoDocumentString struct {
+	ID      string  `bson:"_id"`
+	Payload Payload `bson:",inline"`
+}
+
+// stringScheme stores the textual representation of an identifier
+// (26 characters for ULID, 36 for UUID, 24 for ObjectID) as a BSON string.
+type stringScheme struct {
+	name string
+	gen  func() string
+}
+
+func (s *stringScheme) Name() string {
+	return s.name
+}
+
+func (s *stringScheme) NewID() interface{} {
+	return s.gen()
+}
+
+func (s *stringScheme) NewDocument(id interface{}, payload Payload) interface{} {
+	return mongoDocumentString{ID: id.(string), Payload: payload}
+}
+
+func (s *stringScheme) DocumentID(doc interface{}) interface{} {
+	return doc.(mongoDocumentString).ID
+}
+
+func (s *stringScheme) DocumentType() reflect.Type {
+	return reflect.TypeOf(mongoDocumentString{})
+}
+
+func (s *stringScheme) RegisterCodecs(_ *bsoncodec.RegistryBuilder) {}
+
+// timeOrderedStringScheme is a stringScheme of an identifier which textual representation keeps the time order,
+// as the Crockford's base32 of ULIDs and the lowercase hex of ObjectIDs sort the way their bytes do.
+type timeOrderedStringScheme struct {
+	stringScheme
+	genAt func(ts time.Time) string
+	minAt func(ts time.Time) string
+}
+
+func (s *timeOrderedStringScheme) NewIDAt(ts time.Time) interface{} {
+	return s.genAt(ts)
+}
+
+func (s *timeOrderedStringScheme) MinIDAt(ts time.Time) interface{} {
+	return s.minAt(ts)
+}
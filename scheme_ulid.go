@@ -0,0 +1,186 @@
+package main
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"math/rand"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+func init() {
+	// ulid.Make relies on the default entropy, which is monotonic within a millisecond
+	// and is backed by a math/rand source.
+	registerScheme(newULIDScheme("ULID", nil))
+	registerScheme(newULIDScheme("ULID-monotonic", func() io.Reader {
+		return &ulid.LockedMonotonicReader{
+			MonotonicReader: ulid.Monotonic(crand.Reader, 0),
+		}
+	}))
+	registerScheme(newULIDScheme("ULID-crypto", func() io.Reader {
+		return crand.Reader
+	}))
+	registerScheme(newULIDScheme("ULID-mathrand", func() io.Reader {
+		return &lockedReader{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	}))
+}
+
+type mongoDocumentULID struct {
+	ID      ulid.ULID `bson:"_id"`
+	Payload Payload   `bson:",inline"`
+}
+
+// ulidScheme stores ULIDs as binary values of the UUID subtype.
+// The random part of the identifiers is read from the entropy made by newEntropy,
+// when it is nil the default entropy of the ulid package is used.
+type ulidScheme struct {
+	name       string
+	newEntropy func() io.Reader
+	entropy    io.Reader
+}
+
+func newULIDScheme(name string, newEntropy func() io.Reader) *ulidScheme {
+	s := &ulidScheme{
+		name:       name,
+		newEntropy: newEntropy,
+	}
+	if newEntropy != nil {
+		s.entropy = newEntropy()
+	}
+	return s
+}
+
+func (s *ulidScheme) Name() string {
+	return s.name
+}
+
+func (s *ulidScheme) NewID() interface{} {
+	if s.entropy == nil {
+		return ulid.Make()
+	}
+	return ulid.MustNew(ulid.Now(), s.entropy)
+}
+
+func (s *ulidScheme) NewIDAt(ts time.Time) interface{} {
+	entropy := s.entropy
+	if entropy == nil {
+		entropy = ulid.DefaultEntropy()
+	}
+	return ulid.MustNew(ulid.Timestamp(ts), entropy)
+}
+
+// MinIDAt returns the ULID having the timestamp of the given millisecond and zero entropy.
+func (s *ulidScheme) MinIDAt(ts time.Time) interface{} {
+	return minULIDAt(ts)
+}
+
+func minULIDAt(ts time.Time) ulid.ULID {
+	var id ulid.ULID
+	if err := id.SetTime(ulid.Timestamp(ts)); err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// ForWorker returns the scheme with an entropy of its own, the default entropy
+// is replaced with an equivalent monotonic one.
+func (s *ulidScheme) ForWorker(_ int) IDScheme {
+	newEntropy := s.newEntropy
+	if newEntropy == nil {
+		newEntropy = func() io.Reader {
+			return &ulid.LockedMonotonicReader{
+				MonotonicReader: ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0),
+			}
+		}
+	}
+	return &ulidScheme{
+		name:       s.name,
+		newEntropy: s.newEntropy,
+		entropy:    newEntropy(),
+	}
+}
+
+func (s *ulidScheme) NewDocument(id interface{}, payload Payload) interface{} {
+	return mongoDocumentULID{ID: id.(ulid.ULID), Payload: payload}
+}
+
+func (s *ulidScheme) DocumentID(doc interface{}) interface{} {
+	return doc.(mongoDocumentULID).ID
+}
+
+func (s *ulidScheme) DocumentType() reflect.Type {
+	return reflect.TypeOf(mongoDocumentULID{})
+}
+
+func (s *ulidScheme) RegisterCodecs(rb *bsoncodec.RegistryBuilder) {
+	rb.RegisterTypeEncoder(ulidType, bsoncodec.ValueEncoderFunc(ULIDEncodeValue)).
+		RegisterTypeDecoder(ulidType, bsoncodec.ValueDecoderFunc(ULIDDecodeValue))
+}
+
+// lockedReader makes a reader safe for concurrent use.
+type lockedReader struct {
+	mu sync.Mutex
+	r  io.Reader
+}
+
+func (r *lockedReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Read(p)
+}
+
+var ulidType = reflect.TypeOf(ulid.ULID{})
+
+func ULIDEncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != ulidType {
+		return bsoncodec.ValueEncoderError{Name: "ULIDEncodeValue", Types: []reflect.Type{ulidType}, Received: val}
+	}
+	b, ok := val.Interface().(ulid.ULID)
+	if !ok {
+		return fmt.Errorf("failed to convert interface of type %s to %s",
+			reflect.TypeOf(val.Interface()).String(), reflect.TypeOf(b))
+	}
+
+	if err := vw.WriteBinaryWithSubtype(b[:], bsontype.BinaryUUID); err != nil {
+		return fmt.Errorf("failed to write binary: %w", err)
+	}
+	return nil
+}
+
+func ULIDDecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != ulidType {
+		return bsoncodec.ValueDecoderError{Name: "ULIDDecodeValue", Types: []reflect.Type{ulidType}, Received: val}
+	}
+
+	var data []byte
+	var subtype byte
+	var err error
+
+	//nolint:exhaustive // the rest of types are covered by the `default` branch
+	switch vrType := vr.Type(); vrType {
+	case bsontype.Binary:
+		data, subtype, err = vr.ReadBinary()
+		if subtype != bsontype.BinaryUUID {
+			return fmt.Errorf("unsupported binary subtype %v for ULID", subtype)
+		}
+	case bsontype.Null:
+		err = vr.ReadNull()
+	case bsontype.Undefined:
+		err = vr.ReadUndefined()
+	default:
+		return fmt.Errorf("cannot decode %v into a ULID", vrType)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read ULID value: %w", err)
+	}
+	val.Set(reflect.ValueOf(ulid.ULID(data)))
+	return nil
+}
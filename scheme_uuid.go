@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+func init() {
+	uuid.EnableRandPool()
+	registerScheme(new(uuidScheme))
+}
+
+type mongoDocumentUUID struct {
+	ID      uuid.UUID `bson:"_id"`
+	Payload Payload   `bson:",inline"`
+}
+
+// uuidScheme stores random (version 4) UUIDs as binary values of the UUID subtype.
+type uuidScheme struct{}
+
+func (s *uuidScheme) Name() string {
+	return "UUIDv4"
+}
+
+func (s *uuidScheme) NewID() interface{} {
+	return uuid.New()
+}
+
+func (s *uuidScheme) NewDocument(id interface{}, payload Payload) interface{} {
+	return mongoDocumentUUID{ID: id.(uuid.UUID), Payload: payload}
+}
+
+func (s *uuidScheme) DocumentID(doc interface{}) interface{} {
+	return doc.(mongoDocumentUUID).ID
+}
+
+func (s *uuidScheme) DocumentType() reflect.Type {
+	return reflect.TypeOf(mongoDocumentUUID{})
+}
+
+func (s *uuidScheme) RegisterCodecs(rb *bsoncodec.RegistryBuilder) {
+	registerUUIDCodecs(rb)
+}
+
+// registerUUIDCodecs registers the codecs shared by all the schemes based on uuid.UUID.
+func registerUUIDCodecs(rb *bsoncodec.RegistryBuilder) {
+	rb.RegisterTypeEncoder(uuidType, bsoncodec.ValueEncoderFunc(UUIDEncodeValue)).
+		RegisterTypeDecoder(uuidType, bsoncodec.ValueDecoderFunc(UUIDDecodeValue))
+}
+
+var uuidType = reflect.TypeOf(uuid.UUID{})
+
+func UUIDEncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != uuidType {
+		return bsoncodec.ValueEncoderError{Name: "UUIDEncodeValue", Types: []reflect.Type{uuidType}, Received: val}
+	}
+	b, ok := val.Interface().(uuid.UUID)
+	if !ok {
+		return fmt.Errorf("failed to convert interface of type %s to %s",
+			reflect.TypeOf(val.Interface()).String(), reflect.TypeOf(b))
+	}
+
+	if err := vw.WriteBinaryWithSubtype(b[:], bsontype.BinaryUUID); err != nil {
+		return fmt.Errorf("failed to write binary: %w", err)
+	}
+	return nil
+}
+
+func UUIDDecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != uuidType {
+		return bsoncodec.ValueDecoderError{Name: "UUIDDecodeValue", Types: []reflect.Type{uuidType}, Received: val}
+	}
+
+	var data []byte
+	var subtype byte
+	var err error
+
+	//nolint:exhaustive // the rest of types are covered by the `default` branch
+	switch vrType := vr.Type(); vrType {
+	case bsontype.Binary:
+		data, subtype, err = vr.ReadBinary()
+		if subtype != bsontype.BinaryUUID {
+			return fmt.Errorf("unsupported binary subtype %v for UUID", subtype)
+		}
+	case bsontype.Null:
+		err = vr.ReadNull()
+	case bsontype.Undefined:
+		err = vr.ReadUndefined()
+	default:
+		return fmt.Errorf("cannot decode %v into a UUID", vrType)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read UUID value: %w", err)
+	}
+	uuidBytes, err := uuid.FromBytes(data)
+	if err != nil {
+		return fmt.Errorf("failed to read UUID from bytes: %w", err)
+	}
+	val.Set(reflect.ValueOf(uuidBytes))
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"reflect"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+)
+
+func init() {
+	registerScheme(new(uuidV1Scheme))
+}
+
+type mongoDocumentUUIDv1 struct {
+	ID      uuid.UUID `bson:"_id"`
+	Payload Payload   `bson:",inline"`
+}
+
+// uuidV1Scheme stores time-based (version 1) UUIDs, which start with the low bits of the timestamp,
+// as binary values of the UUID subtype.
+type uuidV1Scheme struct{}
+
+func (s *uuidV1Scheme) Name() string {
+	return "UUIDv1"
+}
+
+func (s *uuidV1Scheme) NewID() interface{} {
+	return uuid.Must(uuid.NewUUID())
+}
+
+func (s *uuidV1Scheme) NewDocument(id interface{}, payload Payload) interface{} {
+	return mongoDocumentUUIDv1{ID: id.(uuid.UUID), Payload: payload}
+}
+
+func (s *uuidV1Scheme) DocumentID(doc interface{}) interface{} {
+	return doc.(mongoDocumentUUIDv1).ID
+}
+
+func (s *uuidV1Scheme) DocumentType() reflect.Type {
+	return reflect.TypeOf(mongoDocumentUUIDv1{})
+}
+
+func (s *uuidV1Scheme) RegisterCodecs(rb *bsoncodec.RegistryBuilder) {
+	registerUUIDCodecs(rb)
+}
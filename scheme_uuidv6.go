@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/binary"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+)
+
+func init() {
+	registerScheme(new(uuidV6Scheme))
+}
+
+type mongoDocumentUUIDv6 struct {
+	ID      uuid.UUID `bson:"_id"`
+	Payload Payload   `bson:",inline"`
+}
+
+// uuidV6Scheme stores field-compatible version 6 UUIDs, which start with the high bits of the timestamp,
+// as binary values of the UUID subtype.
+type uuidV6Scheme struct{}
+
+func (s *uuidV6Scheme) Name() string {
+	return "UUIDv6"
+}
+
+func (s *uuidV6Scheme) NewID() interface{} {
+	return uuid.Must(uuid.NewV6())
+}
+
+// NewIDAt returns a random UUID having the timestamp of the given time in the RFC 9562 layout of version 6.
+func (s *uuidV6Scheme) NewIDAt(ts time.Time) interface{} {
+	id := uuid.New()
+	putUUIDv6Timestamp(&id, ts)
+	return id
+}
+
+// MinIDAt returns the UUID having the timestamp of the given time and zero in the rest of the bits.
+func (s *uuidV6Scheme) MinIDAt(ts time.Time) interface{} {
+	var id uuid.UUID
+	putUUIDv6Timestamp(&id, ts)
+	return id
+}
+
+func (s *uuidV6Scheme) NewDocument(id interface{}, payload Payload) interface{} {
+	return mongoDocumentUUIDv6{ID: id.(uuid.UUID), Payload: payload}
+}
+
+func (s *uuidV6Scheme) DocumentID(doc interface{}) interface{} {
+	return doc.(mongoDocumentUUIDv6).ID
+}
+
+func (s *uuidV6Scheme) DocumentType() reflect.Type {
+	return reflect.TypeOf(mongoDocumentUUIDv6{})
+}
+
+func (s *uuidV6Scheme) RegisterCodecs(rb *bsoncodec.RegistryBuilder) {
+	registerUUIDCodecs(rb)
+}
+
+// uuidGregorianOffset is the number of 100-nanosecond intervals between the start of the Gregorian calendar,
+// which the timestamps of the UUIDs count from, and the Unix epoch.
+const uuidGregorianOffset = 122192928000000000
+
+// putUUIDv6Timestamp puts the 60-bit timestamp of the given time into the time_high, time_mid and time_low fields
+// of the UUID, highest bits first, along with the version.
+func putUUIDv6Timestamp(id *uuid.UUID, ts time.Time) {
+	t := uint64(ts.UnixNano()/100) + uuidGregorianOffset
+	binary.BigEndian.PutUint32(id[0:4], uint32(t>>28))
+	binary.BigEndian.PutUint16(id[4:6], uint16(t>>12))
+	binary.BigEndian.PutUint16(id[6:8], 0x6000|uint16(t&0x0fff))
+}
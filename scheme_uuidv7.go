@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/binary"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+)
+
+func init() {
+	registerScheme(new(uuidV7Scheme))
+}
+
+type mongoDocumentUUIDv7 struct {
+	ID      uuid.UUID `bson:"_id"`
+	Payload Payload   `bson:",inline"`
+}
+
+// uuidV7Scheme stores time-ordered (version 7) UUIDs as binary values of the UUID subtype.
+type uuidV7Scheme struct{}
+
+func (s *uuidV7Scheme) Name() string {
+	return "UUIDv7"
+}
+
+func (s *uuidV7Scheme) NewID() interface{} {
+	return uuid.Must(uuid.NewV7())
+}
+
+// NewIDAt returns a random UUID having the Unix timestamp of the given millisecond
+// in the first 48 bits and the version set to 7.
+func (s *uuidV7Scheme) NewIDAt(ts time.Time) interface{} {
+	id := uuid.New()
+	putUUIDv7Timestamp(&id, ts)
+	id[6] = id[6]&0x0f | 0x70
+	return id
+}
+
+// MinIDAt returns the UUID having the timestamp of the given millisecond and zero in the rest of the bits.
+func (s *uuidV7Scheme) MinIDAt(ts time.Time) interface{} {
+	var id uuid.UUID
+	putUUIDv7Timestamp(&id, ts)
+	return id
+}
+
+func (s *uuidV7Scheme) NewDocument(id interface{}, payload Payload) interface{} {
+	return mongoDocumentUUIDv7{ID: id.(uuid.UUID), Payload: payload}
+}
+
+func (s *uuidV7Scheme) DocumentID(doc interface{}) interface{} {
+	return doc.(mongoDocumentUUIDv7).ID
+}
+
+func (s *uuidV7Scheme) DocumentType() reflect.Type {
+	return reflect.TypeOf(mongoDocumentUUIDv7{})
+}
+
+func (s *uuidV7Scheme) RegisterCodecs(rb *bsoncodec.RegistryBuilder) {
+	registerUUIDCodecs(rb)
+}
+
+func putUUIDv7Timestamp(id *uuid.UUID, ts time.Time) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(ts.UnixMilli()))
+	copy(id[0:6], b[2:8])
+}
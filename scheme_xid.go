@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/rs/xid"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+func init() {
+	registerScheme(new(xidScheme))
+}
+
+type mongoDocumentXID struct {
+	ID      xid.ID  `bson:"_id"`
+	Payload Payload `bson:",inline"`
+}
+
+// xidScheme stores 12-byte XIDs as generic binary values.
+type xidScheme struct{}
+
+func (s *xidScheme) Name() string {
+	return "XID"
+}
+
+func (s *xidScheme) NewID() interface{} {
+	return xid.New()
+}
+
+func (s *xidScheme) NewIDAt(ts time.Time) interface{} {
+	return xid.NewWithTime(ts)
+}
+
+// MinIDAt returns the XID having the timestamp of the given second and zero in the rest of the bytes.
+func (s *xidScheme) MinIDAt(ts time.Time) interface{} {
+	var id xid.ID
+	binary.BigEndian.PutUint32(id[0:4], uint32(ts.Unix()))
+	return id
+}
+
+func (s *xidScheme) NewDocument(id interface{}, payload Payload) interface{} {
+	return mongoDocumentXID{ID: id.(xid.ID), Payload: payload}
+}
+
+func (s *xidScheme) DocumentID(doc interface{}) interface{} {
+	return doc.(mongoDocumentXID).ID
+}
+
+func (s *xidScheme) DocumentType() reflect.Type {
+	return reflect.TypeOf(mongoDocumentXID{})
+}
+
+func (s *xidScheme) RegisterCodecs(rb *bsoncodec.RegistryBuilder) {
+	rb.RegisterTypeEncoder(xidType, bsoncodec.ValueEncoderFunc(XIDEncodeValue)).
+		RegisterTypeDecoder(xidType, bsoncodec.ValueDecoderFunc(XIDDecodeValue))
+}
+
+var xidType = reflect.TypeOf(xid.ID{})
+
+func XIDEncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != xidType {
+		return bsoncodec.ValueEncoderError{Name: "XIDEncodeValue", Types: []reflect.Type{xidType}, Received: val}
+	}
+	b, ok := val.Interface().(xid.ID)
+	if !ok {
+		return fmt.Errorf("failed to convert interface of type %s to %s",
+			reflect.TypeOf(val.Interface()).String(), reflect.TypeOf(b))
+	}
+
+	if err := vw.WriteBinaryWithSubtype(b[:], bsontype.BinaryGeneric); err != nil {
+		return fmt.Errorf("failed to write binary: %w", err)
+	}
+	return nil
+}
+
+func XIDDecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != xidType {
+		return bsoncodec.ValueDecoderError{Name: "XIDDecodeValue", Types: []reflect.Type{xidType}, Received: val}
+	}
+
+	var data []byte
+	var subtype byte
+	var err error
+
+	//nolint:exhaustive // the rest of types are covered by the `default` branch
+	switch vrType := vr.Type(); vrType {
+	case bsontype.Binary:
+		data, subtype, err = vr.ReadBinary()
+		if subtype != bsontype.BinaryGeneric {
+			return fmt.Errorf("unsupported binary subtype %v for XID", subtype)
+		}
+	case bsontype.Null:
+		err = vr.ReadNull()
+	case bsontype.Undefined:
+		err = vr.ReadUndefined()
+	default:
+		return fmt.Errorf("cannot decode %v into a XID", vrType)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read XID value: %w", err)
+	}
+	id, err := xid.FromBytes(data)
+	if err != nil {
+		return fmt.Errorf("failed to read XID from bytes: %w", err)
+	}
+	val.Set(reflect.ValueOf(id))
+	return nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+)
+
+// baselineSchemeName is the name of the scheme every other scheme is compared against.
+const baselineSchemeName = "ObjectId"
+
+// IDScheme describes a document identifier type taking part in the tests.
+// Adding a new identifier type comes down to implementing this interface
+// and calling registerScheme from the init function of the scheme file.
+type IDScheme interface {
+	// Name returns the scheme name used in the reports.
+	Name() string
+	// NewID generates a new identifier.
+	NewID() interface{}
+	// NewDocument returns a mongo document having the given identifier as `_id`
+	// and the fields of the payload inlined.
+	NewDocument(id interface{}, payload Payload) interface{}
+	// DocumentID returns the identifier of a document created by NewDocument.
+	DocumentID(doc interface{}) interface{}
+	// DocumentType returns the type of the documents created by NewDocument, which the read documents are decoded into.
+	DocumentType() reflect.Type
+	// RegisterCodecs registers the BSON codecs the identifier type requires.
+	RegisterCodecs(rb *bsoncodec.RegistryBuilder)
+}
+
+// workerScheme is implemented by the schemes which generators keep a state, e.g. a sequence
+// or a monotonic entropy source, to give every concurrent worker a generator of its own.
+type workerScheme interface {
+	// ForWorker returns an independent copy of the scheme having the same name.
+	ForWorker(worker int) IDScheme
+}
+
+// schemeForWorker returns the scheme with a generator of the worker's own when the scheme supports it.
+func schemeForWorker(s IDScheme, worker int) IDScheme {
+	if ws, ok := s.(workerScheme); ok {
+		return ws.ForWorker(worker)
+	}
+	return s
+}
+
+// timeOrderedScheme is implemented by the schemes which identifiers embed their creation time
+// and sort by it, so that the documents created within a time range make up a range of identifiers.
+type timeOrderedScheme interface {
+	// NewIDAt generates a new identifier created at the given time.
+	NewIDAt(ts time.Time) interface{}
+	// MinIDAt returns the lowest identifier of the given time, to be used as a range bound.
+	MinIDAt(ts time.Time) interface{}
+}
+
+var schemeRegistry []IDScheme
+
+func registerScheme(s IDScheme) {
+	for _, registered := range schemeRegistry {
+		if registered.Name() == s.Name() {
+			panic(fmt.Errorf("scheme %s is already registered", s.Name()))
+		}
+	}
+	schemeRegistry = append(schemeRegistry, s)
+}
+
+// registeredSchemes returns all the registered schemes with the baseline scheme first
+// and the rest of them ordered by name.
+func registeredSchemes() []IDScheme {
+	result := make([]IDScheme, len(schemeRegistry))
+	copy(result, schemeRegistry)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Name() == baselineSchemeName || result[j].Name() == baselineSchemeName {
+			return result[i].Name() == baselineSchemeName
+		}
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
+
+// selectSchemes returns the registered schemes having the given names keeping
+// the order of registeredSchemes, all of them are returned when names is empty.
+func selectSchemes(names []string) ([]IDScheme, error) {
+	all := registeredSchemes()
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	for _, name := range names {
+		if !contains(schemeNames(all), name) {
+			return nil, fmt.Errorf("unknown scheme %q", name)
+		}
+	}
+
+	var result []IDScheme
+	for _, s := range all {
+		if contains(names, s.Name()) {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func schemeNames(schemes []IDScheme) []string {
+	result := make([]string, len(schemes))
+	for i, s := range schemes {
+		result[i] = s.Name()
+	}
+	return result
+}
+
+func generateDocs(s IDScheme, n int, payloads *payloadGenerator) []interface{} {
+	result := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		result[i] = s.NewDocument(s.NewID(), payloads.Next())
+	}
+	return result
+}
+
+// timedDocument is a document carrying its creation time, which the time range queries select by
+// when the identifiers are not time ordered.
+type timedDocument struct {
+	ID        interface{} `bson:"_id"`
+	CreatedAt time.Time   `bson:"createdAt"`
+	Payload   Payload     `bson:",inline"`
+}
+
+// generateTimedDocs generates n documents created evenly over the span ending at end,
+// the identifiers of the time ordered schemes are created at the time of their documents.
+func generateTimedDocs(s IDScheme, n int, end time.Time, span time.Duration, payloads *payloadGenerator) []interface{} {
+	ts, timeOrdered := s.(timeOrderedScheme)
+	start := end.Add(-span)
+
+	result := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		createdAt := start.Add(time.Duration(float64(span) * float64(i) / float64(n)))
+		doc := timedDocument{CreatedAt: createdAt, Payload: payloads.Next()}
+		if timeOrdered {
+			doc.ID = ts.NewIDAt(createdAt)
+		} else {
+			doc.ID = s.NewID()
+		}
+		result[i] = doc
+	}
+	return result
+}
+
+func pickRandomIDs(s IDScheme, docs []interface{}, n int) []interface{} {
+	docsLen := len(docs)
+	var result = make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		result[i] = s.DocumentID(docs[rand.Intn(docsLen)])
+	}
+	return result
+}
+
+// insertionOrder joins the batches of documents in the order they were inserted in.
+func insertionOrder(batches ...[]interface{}) []interface{} {
+	var n int
+	for _, batch := range batches {
+		n += len(batch)
+	}
+	result := make([]interface{}, 0, n)
+	for _, batch := range batches {
+		result = append(result, batch...)
+	}
+	return result
+}
+
+// pickIDs picks n identifiers with the key chooser, the documents are ordered by their insertion time.
+func pickIDs(s IDScheme, docs []interface{}, n int, chooser keyChooser, r *rand.Rand) []interface{} {
+	result := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		result[i] = s.DocumentID(docs[chooser.Next(r, len(docs))])
+	}
+	return result
+}
+
+// pickRecentIDs picks n random identifiers among the recent documents, which are the last ones of docs.
+func pickRecentIDs(s IDScheme, docs []interface{}, n, recent int) []interface{} {
+	if recent > len(docs) {
+		recent = len(docs)
+	}
+	return pickRandomIDs(s, docs[len(docs)-recent:], n)
+}
+
+// pickDistinctIDs picks n random identifiers of distinct documents, all of them when n exceeds their number.
+func pickDistinctIDs(s IDScheme, docs []interface{}, n int) []interface{} {
+	if n >= len(docs) {
+		n = len(docs)
+	}
+	result := make([]interface{}, 0, n)
+	if 2*n > len(docs) {
+		for _, i := range rand.Perm(len(docs))[:n] {
+			result = append(result, s.DocumentID(docs[i]))
+		}
+		return result
+	}
+
+	picked := make(map[int]bool, n)
+	for len(result) < n {
+		i := rand.Intn(len(docs))
+		if picked[i] {
+			continue
+		}
+		picked[i] = true
+		result = append(result, s.DocumentID(docs[i]))
+	}
+	return result
+}
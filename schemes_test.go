@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// compareIDs compares two identifiers of a scheme the way mongo compares their BSON values:
+// numerically, by the bytes of the strings or by the bytes of the binary values.
+func compareIDs(a, b interface{}) int {
+	switch x := a.(type) {
+	case int64:
+		y := b.(int64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case string:
+		return bytes.Compare([]byte(x), []byte(b.(string)))
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() != reflect.Array || va.Type() != vb.Type() {
+		panic(fmt.Errorf("cannot compare %T with %T", a, b))
+	}
+	ba, bb := make([]byte, va.Len()), make([]byte, vb.Len())
+	reflect.Copy(reflect.ValueOf(ba), va)
+	reflect.Copy(reflect.ValueOf(bb), vb)
+	return bytes.Compare(ba, bb)
+}
+
+func TestTimedDocsWithinIDBounds(t *testing.T) {
+	end := time.Now()
+	for _, s := range registeredSchemes() {
+		ts, ok := s.(timeOrderedScheme)
+		if !ok {
+			continue
+		}
+		t.Run(s.Name(), func(t *testing.T) {
+			for i, doc := range generateTimedDocs(s, 1000, end, 30*24*time.Hour, nil) {
+				d := doc.(timedDocument)
+				// the timestamps of some schemes are of a second precision
+				from, to := ts.MinIDAt(d.CreatedAt), ts.MinIDAt(d.CreatedAt.Add(time.Second))
+				if compareIDs(d.ID, from) < 0 || compareIDs(d.ID, to) >= 0 {
+					t.Fatalf("ID %v of document %d created at %s is out of [%v, %v)", d.ID, i, d.CreatedAt, from, to)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeOrderedSchemes(t *testing.T) {
+	want := []string{"ObjectId", "KSUID", "ObjectId-hex", "Snowflake", "ULID", "ULID-crypto", "ULID-mathrand",
+		"ULID-monotonic", "ULID-str", "UUIDv6", "UUIDv7", "XID"}
+	var got []string
+	for _, s := range registeredSchemes() {
+		if _, ok := s.(timeOrderedScheme); ok {
+			got = append(got, s.Name())
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("time ordered schemes are %v, want %v", got, want)
+	}
+}
+
+func TestPickLatestInsertedIDs(t *testing.T) {
+	s := new(objectIDScheme)
+	fixtures := generateDocs(s, 950, nil)
+	docs := generateDocs(s, 50, nil)
+	chooser, err := newKeyChooser("latest-5%", len(fixtures)+len(docs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inserted := make(map[interface{}]bool, len(docs))
+	for _, doc := range docs {
+		inserted[s.DocumentID(doc)] = true
+	}
+	picked := make(map[interface{}]bool, len(docs))
+	r := rand.New(rand.NewSource(1))
+	for _, id := range pickIDs(s, insertionOrder(fixtures, docs), 10000, chooser, r) {
+		if !inserted[id] {
+			t.Fatalf("ID %v of the latest 5%% is not one of the inserted documents", id)
+		}
+		picked[id] = true
+	}
+	if len(picked) != len(docs) {
+		t.Errorf("%d of the %d inserted documents are picked, want all of them", len(picked), len(docs))
+	}
+}
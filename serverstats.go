@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// serverStatus holds the serverStatus counters the tests are interested in.
+type serverStatus struct {
+	Metrics struct {
+		Operation struct {
+			WriteConflicts int64 `bson:"writeConflicts"`
+		} `bson:"operation"`
+	} `bson:"metrics"`
+	WiredTiger wiredTigerStats `bson:"wiredTiger"`
+}
+
+func (t *Tester) getServerStatus() (*serverStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	status := new(serverStatus)
+	res := t.Coll.Database().RunCommand(ctx, bson.D{{Key: "serverStatus", Value: 1}})
+	if err := res.Decode(status); err != nil {
+		return nil, fmt.Errorf("failed to get server status: %w", err)
+	}
+	return status, nil
+}
+
+// The phases of the scenarios the WiredTiger counters are recorded over, besides batchPhasePrepare and batchPhaseInsert.
+const (
+	phaseIndexedInsert = "indexed-insert"
+	phaseGet           = "get"
+	phaseLookup        = "lookup"
+	phasePagination    = "pagination"
+	phaseRange         = "range"
+	phaseOperations    = "operations"
+	phaseWorkload      = "workload"
+)
+
+// namespaceNotFoundErrorCode is returned by collStats of a collection which does not exist yet on older servers.
+const namespaceNotFoundErrorCode = 26
+
+// EngineStats holds the WiredTiger counters showing how local the accesses to the B-trees are,
+// scattered keys make more pages be read into the cache, evicted and split.
+type EngineStats struct {
+	PagesReadIntoCache int64 `json:"pagesReadIntoCache"`
+	PagesEvicted       int64 `json:"pagesEvicted"`
+	// LeafPageSplits counts both the leaf pages split in memory, as the inserts fill them, and the ones split
+	// during eviction.
+	LeafPageSplits    int64 `json:"leafPageSplits"`
+	BytesReadFromDisk int64 `json:"bytesReadFromDisk"`
+}
+
+func (s EngineStats) add(o EngineStats) EngineStats {
+	return EngineStats{
+		PagesReadIntoCache: s.PagesReadIntoCache + o.PagesReadIntoCache,
+		PagesEvicted:       s.PagesEvicted + o.PagesEvicted,
+		LeafPageSplits:     s.LeafPageSplits + o.LeafPageSplits,
+		BytesReadFromDisk:  s.BytesReadFromDisk + o.BytesReadFromDisk,
+	}
+}
+
+func (s EngineStats) sub(o EngineStats) EngineStats {
+	return EngineStats{
+		PagesReadIntoCache: s.PagesReadIntoCache - o.PagesReadIntoCache,
+		PagesEvicted:       s.PagesEvicted - o.PagesEvicted,
+		LeafPageSplits:     s.LeafPageSplits - o.LeafPageSplits,
+		BytesReadFromDisk:  s.BytesReadFromDisk - o.BytesReadFromDisk,
+	}
+}
+
+// wiredTigerStats holds the sections of the WiredTiger statistics the EngineStats are taken from,
+// they are the same in serverStatus, collStats and the index details of collStats.
+type wiredTigerStats struct {
+	Cache struct {
+		PagesReadIntoCache     int64 `bson:"pages read into cache"`
+		ModifiedPagesEvicted   int64 `bson:"modified pages evicted"`
+		UnmodifiedPagesEvicted int64 `bson:"unmodified pages evicted"`
+		InMemoryPageSplits     int64 `bson:"in-memory page splits"`
+		LeafPagesSplit         int64 `bson:"leaf pages split during eviction"`
+	} `bson:"cache"`
+	BlockManager struct {
+		BytesRead int64 `bson:"bytes read"`
+	} `bson:"block-manager"`
+}
+
+func (w wiredTigerStats) engineStats() EngineStats {
+	return EngineStats{
+		PagesReadIntoCache: w.Cache.PagesReadIntoCache,
+		PagesEvicted:       w.Cache.ModifiedPagesEvicted + w.Cache.UnmodifiedPagesEvicted,
+		LeafPageSplits:     w.Cache.InMemoryPageSplits + w.Cache.LeafPagesSplit,
+		BytesReadFromDisk:  w.BlockManager.BytesRead,
+	}
+}
+
+// engineStatsField is a WiredTiger counter reported for every scheme.
+type engineStatsField struct {
+	// title names the counter in the table and metric in the measurements.
+	title  string
+	metric string
+	// bytes is set for the counters of bytes rather than of pages.
+	bytes bool
+	value func(s EngineStats) int64
+}
+
+var engineStatsFields = []engineStatsField{
+	{"pages read into cache", "pagesReadIntoCache", false, func(s EngineStats) int64 { return s.PagesReadIntoCache }},
+	{"pages evicted", "pagesEvicted", false, func(s EngineStats) int64 { return s.PagesEvicted }},
+	{"leaf page splits", "leafPageSplits", false, func(s EngineStats) int64 { return s.LeafPageSplits }},
+	{"bytes read from disk", "bytesReadFromDisk", true, func(s EngineStats) int64 { return s.BytesReadFromDisk }},
+}
+
+// engineStatsValues returns the values of the counter keyed by the scheme name, a sample per trial.
+func engineStatsValues(stats map[string][]EngineStats, value func(s EngineStats) int64) map[string][]int64 {
+	result := make(map[string][]int64, len(stats))
+	for name, trials := range stats {
+		for _, s := range trials {
+			result[name] = append(result[name], value(s))
+		}
+	}
+	return result
+}
+
+// PhaseEngineStats holds the changes of the WiredTiger counters over a phase of a scenario.
+type PhaseEngineStats struct {
+	Phase string `json:"phase"`
+	// Server are keyed by the scheme name and hold the changes of the counters of the whole server, a sample per trial.
+	Server map[string][]EngineStats `json:"server"`
+	// Collection are keyed by the scheme name and hold the changes of the counters of the B-trees
+	// of the collection and all its indexes, a sample per trial.
+	Collection map[string][]EngineStats `json:"collection"`
+}
+
+// engineSnapshot holds the WiredTiger counters at a moment.
+type engineSnapshot struct {
+	server     EngineStats
+	collection EngineStats
+}
+
+func (t *Tester) getEngineSnapshot() (*engineSnapshot, error) {
+	status, err := t.getServerStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var coll struct {
+		WiredTiger   wiredTigerStats            `bson:"wiredTiger"`
+		IndexDetails map[string]wiredTigerStats `bson:"indexDetails"`
+	}
+	cmd := bson.D{{Key: "collStats", Value: t.Coll.Name()}, {Key: "indexDetails", Value: true}}
+	err = t.Coll.Database().RunCommand(ctx, cmd).Decode(&coll)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == namespaceNotFoundErrorCode {
+		// the counters of a collection which does not exist yet are all zero
+		err = nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection stats: %w", err)
+	}
+
+	snapshot := &engineSnapshot{server: status.WiredTiger.engineStats(), collection: coll.WiredTiger.engineStats()}
+	for _, index := range coll.IndexDetails {
+		snapshot.collection = snapshot.collection.add(index.engineStats())
+	}
+	return snapshot, nil
+}
+
+// recordPhase runs the phase of the scheme and records the changes of the WiredTiger counters over it
+// into the stats of the phase, which are added to the stats on the first record of the phase.
+// The phase is only run when recording the counters is turned off.
+func (t *Tester) recordPhase(stats *[]*PhaseEngineStats, phase, scheme string, run func() error) error {
+	if !t.Config.EngineStats {
+		return run()
+	}
+
+	before, err := t.getEngineSnapshot()
+	if err != nil {
+		return err
+	}
+	if err = run(); err != nil {
+		return err
+	}
+	after, err := t.getEngineSnapshot()
+	if err != nil {
+		return err
+	}
+
+	var ps *PhaseEngineStats
+	for _, s := range *stats {
+		if s.Phase == phase {
+			ps = s
+			break
+		}
+	}
+	if ps == nil {
+		ps = &PhaseEngineStats{
+			Phase:      phase,
+			Server:     make(map[string][]EngineStats, len(t.Schemes)),
+			Collection: make(map[string][]EngineStats, len(t.Schemes)),
+		}
+		*stats = append(*stats, ps)
+	}
+	ps.Server[scheme] = append(ps.Server[scheme], after.server.sub(before.server))
+	ps.Collection[scheme] = append(ps.Collection[scheme], after.collection.sub(before.collection))
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// Stats summarizes the samples of a measurement repeated over several trials.
+type Stats struct {
+	N      int
+	Mean   float64
+	StdDev float64
+	Min    float64
+	Max    float64
+	// CI95 is the half-width of the 95% confidence interval of the mean.
+	CI95 float64
+}
+
+func newStats(samples []float64) Stats {
+	st := Stats{N: len(samples)}
+	if st.N == 0 {
+		return st
+	}
+
+	st.Min, st.Max = samples[0], samples[0]
+	var sum float64
+	for _, v := range samples {
+		sum += v
+		st.Min = math.Min(st.Min, v)
+		st.Max = math.Max(st.Max, v)
+	}
+	st.Mean = sum / float64(st.N)
+
+	if st.N > 1 {
+		st.StdDev = math.Sqrt(sampleVariance(samples, st.Mean))
+		st.CI95 = tQuantile975(float64(st.N-1)) * st.StdDev / math.Sqrt(float64(st.N))
+	}
+	return st
+}
+
+func sampleVariance(samples []float64, mean float64) float64 {
+	var sum float64
+	for _, v := range samples {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum / float64(len(samples)-1)
+}
+
+// significantDiff reports whether the means of two samples differ
+// at the 95% confidence level according to Welch's t-test.
+func significantDiff(a, b []float64) bool {
+	if len(a) < 2 || len(b) < 2 {
+		return false
+	}
+	sa, sb := newStats(a), newStats(b)
+	va := sa.StdDev * sa.StdDev / float64(sa.N)
+	vb := sb.StdDev * sb.StdDev / float64(sb.N)
+	if va+vb == 0 {
+		return sa.Mean != sb.Mean
+	}
+
+	t := math.Abs(sa.Mean-sb.Mean) / math.Sqrt(va+vb)
+	// Welch–Satterthwaite degrees of freedom
+	df := (va + vb) * (va + vb) / (va*va/float64(sa.N-1) + vb*vb/float64(sb.N-1))
+	return t > tQuantile975(df)
+}
+
+// tQuantiles975 holds the 0.975 quantiles of Student's t-distribution for 1 to 30 degrees of freedom.
+var tQuantiles975 = []float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// tQuantile975 returns the 0.975 quantile of Student's t-distribution,
+// rounding the degrees of freedom down to stay on the conservative side.
+func tQuantile975(df float64) float64 {
+	if df > 1000 {
+		// converting the huge and infinite values to int is undefined
+		df = 1000
+	}
+	switch n := int(df); {
+	case n < 1:
+		return tQuantiles975[0]
+	case n <= len(tQuantiles975):
+		return tQuantiles975[n-1]
+	case n < 40:
+		return tQuantiles975[len(tQuantiles975)-1]
+	case n < 60:
+		return 2.021
+	case n < 120:
+		return 2.000
+	case n < 1000:
+		return 1.980
+	default:
+		return 1.960
+	}
+}
+
+func durationSamples(d []time.Duration) []float64 {
+	result := make([]float64, len(d))
+	for i, v := range d {
+		result[i] = float64(v)
+	}
+	return result
+}
+
+func int64Samples(v []int64) []float64 {
+	result := make([]float64, len(v))
+	for i, n := range v {
+		result[i] = float64(n)
+	}
+	return result
+}
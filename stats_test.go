@@ -0,0 +1,79 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewStats(t *testing.T) {
+	st := newStats([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	if st.N != 8 || st.Mean != 5 || st.Min != 2 || st.Max != 9 {
+		t.Fatalf("stats = %+v, want N=8 mean=5 min=2 max=9", st)
+	}
+	stdDev := math.Sqrt(32.0 / 7)
+	if math.Abs(st.StdDev-stdDev) > 1e-9 {
+		t.Errorf("stddev = %v, want the sample one %v", st.StdDev, stdDev)
+	}
+	if ci := 2.365 * stdDev / math.Sqrt(8); math.Abs(st.CI95-ci) > 1e-9 {
+		t.Errorf("CI95 = %v, want %v", st.CI95, ci)
+	}
+
+	if st := newStats(nil); st != (Stats{}) {
+		t.Errorf("stats of no samples = %+v, want zero", st)
+	}
+	if st := newStats([]float64{3}); st.Mean != 3 || st.StdDev != 0 || st.CI95 != 0 {
+		t.Errorf("stats of a single sample = %+v, want mean 3 and no spread", st)
+	}
+}
+
+func TestTQuantile975(t *testing.T) {
+	tests := []struct {
+		df   float64
+		want float64
+	}{
+		{0.5, 12.706},
+		{1, 12.706},
+		{2, 4.303},
+		// the degrees of freedom are rounded down
+		{5.9, 2.571},
+		{30, 2.042},
+		{39, 2.042},
+		{40, 2.021},
+		{60, 2.000},
+		{120, 1.980},
+		{1000, 1.960},
+		{math.Inf(1), 1.960},
+	}
+	for _, tt := range tests {
+		if got := tQuantile975(tt.df); got != tt.want {
+			t.Errorf("tQuantile975(%v) = %v, want %v", tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestSignificantDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want bool
+	}{
+		{"too few samples", []float64{1}, []float64{100, 101}, false},
+		{"same constant samples", []float64{5, 5, 5}, []float64{5, 5, 5}, false},
+		{"different constant samples", []float64{5, 5, 5}, []float64{6, 6, 6}, true},
+		{"separated samples", []float64{10, 11, 12}, []float64{20, 21, 22}, true},
+		{"overlapping samples", []float64{10, 12, 14}, []float64{11, 13, 15}, false},
+		// t = 2.6 with 9.2 Welch–Satterthwaite degrees of freedom, it would not be significant with
+		// the degrees of freedom of the smaller sample
+		{"unequal variances", []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, []float64{7.9, 8.1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := significantDiff(tt.a, tt.b); got != tt.want {
+				t.Errorf("significantDiff = %v, want %v", got, tt.want)
+			}
+			if got := significantDiff(tt.b, tt.a); got != tt.want {
+				t.Errorf("significantDiff of swapped samples = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
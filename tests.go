@@ -4,129 +4,159 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"reflect"
 	"time"
 
-	"github.com/google/uuid"
-	"github.com/oklog/ulid/v2"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func init() {
-	uuid.EnableRandPool()
-}
-
-type mongoDocumentULID struct {
-	ID ulid.ULID `bson:"_id"`
-}
-type mongoDocumentUUID struct {
-	ID uuid.UUID `bson:"_id"`
-}
-type mongoDocumentObjectID struct {
-	ID primitive.ObjectID `bson:"_id"`
-}
-
 type TesterResults struct {
-	InsertsBatched1M1K        *InsertBatchesTestResult
-	InsertsBatched1M5K        *InsertBatchesTestResult
-	InsertsBatched1M10K       *InsertBatchesTestResult
-	Insert1M                  *InsertTestResult
-	InsertsBatchedPres10M10K  *InsertBatchesWithPresentTestResult
-	InsertsBatchedPres10M100K *InsertBatchesWithPresentTestResult
+	Metadata RunMetadata `json:"metadata"`
+	// Schemes lists the names of the tested schemes, the baseline scheme goes first.
+	Schemes []string `json:"schemes"`
+	// Repetitions is the number of trials every scenario was run for.
+	Repetitions int `json:"repetitions"`
+
+	InsertsBatched            []*InsertBatchesTestResult            `json:"insertsBatched,omitempty"`
+	Inserts                   *InsertTestResult                     `json:"inserts,omitempty"`
+	InsertsBatchedWithPresent []*InsertBatchesWithPresentTestResult `json:"insertsBatchedWithPresent,omitempty"`
+	ConcurrentInserts         []*ConcurrentInsertsTestResult        `json:"concurrentInserts,omitempty"`
+	UpdatesDeletes            *UpdatesDeletesTestResult             `json:"updatesDeletes,omitempty"`
+	MixedWorkloads            []*MixedWorkloadTestResult            `json:"mixedWorkloads,omitempty"`
+	SecondaryIndexes          *SecondaryIndexesTestResult           `json:"secondaryIndexes,omitempty"`
 }
 
 type Tester struct {
-	Coll *mongo.Collection
+	Coll    *mongo.Collection
+	Schemes []IDScheme
+	Config  *Config
+	// Payloads makes the payloads of the documents of every scheme.
+	Payloads *payloadGenerator
 }
 
 func (t *Tester) Run() (*TesterResults, error) {
-	var err error
-	results := new(TesterResults)
-
-	const (
-		OneMillion      = 1000000
-		TenMillion      = 10 * OneMillion
-		OneThousand     = 1000
-		FiveThousand    = 5 * OneThousand
-		TenThousand     = 10 * OneThousand
-		HundredThousand = 100 * OneThousand
-	)
-
-	results.InsertsBatched1M1K, err = t.testInsertBatches(OneMillion, OneThousand)
-	if err != nil {
-		return nil, fmt.Errorf("failed to run insert batches test: %w", err)
+	results := &TesterResults{
+		Metadata:    t.runMetadata(),
+		Schemes:     schemeNames(t.Schemes),
+		Repetitions: t.Config.Repetitions,
 	}
 
-	results.InsertsBatched1M5K, err = t.testInsertBatches(OneMillion, FiveThousand)
-	if err != nil {
-		return nil, fmt.Errorf("failed to run insert batches test: %w", err)
+	if t.Config.ScenarioEnabled(scenarioInsertBatches) {
+		c := t.Config.InsertBatches
+		for _, batchSize := range c.BatchSizes {
+			r, err := t.testInsertBatches(t.Config.Scaled(c.TotalDocs), batchSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to run insert batches test: %w", err)
+			}
+			results.InsertsBatched = append(results.InsertsBatched, r)
+		}
 	}
 
-	results.InsertsBatched1M10K, err = t.testInsertBatches(OneMillion, TenThousand)
-	if err != nil {
-		return nil, fmt.Errorf("failed to run insert batches test: %w", err)
+	if t.Config.ScenarioEnabled(scenarioInserts) {
+		var err error
+		results.Inserts, err = t.testInserts(t.Config.Scaled(t.Config.Inserts.TotalDocs))
+		if err != nil {
+			return nil, fmt.Errorf("failed to run inserts test: %w", err)
+		}
+	}
+
+	if t.Config.ScenarioEnabled(scenarioInsertBatchesWithPresent) {
+		c := t.Config.InsertBatchesWithPresent
+		for _, batchSize := range c.BatchSizes {
+			r, err := t.testInsertBatchesWithPresent(t.Config.Scaled(c.InsertCount), t.Config.Scaled(c.PresentCount), batchSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to run insert batches with present test: %w", err)
+			}
+			results.InsertsBatchedWithPresent = append(results.InsertsBatchedWithPresent, r)
+		}
+	}
+
+	if t.Config.ScenarioEnabled(scenarioConcurrentInserts) {
+		c := t.Config.ConcurrentInserts
+		for _, workers := range c.Workers {
+			for _, generator := range c.Generators {
+				r, err := t.testConcurrentInserts(t.Config.Scaled(c.TotalDocs), c.BatchSize, workers, generator)
+				if err != nil {
+					return nil, fmt.Errorf("failed to run concurrent inserts test: %w", err)
+				}
+				results.ConcurrentInserts = append(results.ConcurrentInserts, r)
+			}
+		}
 	}
 
-	results.Insert1M, err = t.testInserts(OneMillion)
-	if err != nil {
-		return nil, fmt.Errorf("failed to run inserts test: %w", err)
+	if t.Config.ScenarioEnabled(scenarioUpdatesDeletes) {
+		c := t.Config.UpdatesDeletes
+		var err error
+		results.UpdatesDeletes, err = t.testUpdatesDeletes(t.Config.Scaled(c.PresentCount), t.Config.Scaled(c.RecentCount), t.Config.Scaled(c.Ops))
+		if err != nil {
+			return nil, fmt.Errorf("failed to run updates deletes test: %w", err)
+		}
 	}
 
-	results.InsertsBatchedPres10M10K, err = t.testInsertBatchesWithPresent(TenMillion, TenMillion, TenThousand)
-	if err != nil {
-		return nil, fmt.Errorf("failed to run insert batches with present test: %w", err)
+	if t.Config.ScenarioEnabled(scenarioMixedWorkload) {
+		c := t.Config.MixedWorkload
+		for _, distribution := range c.Distributions {
+			r, err := t.testMixedWorkload(t.Config.Scaled(c.PresentCount), distribution)
+			if err != nil {
+				return nil, fmt.Errorf("failed to run mixed workload test: %w", err)
+			}
+			results.MixedWorkloads = append(results.MixedWorkloads, r)
+		}
 	}
 
-	results.InsertsBatchedPres10M100K, err = t.testInsertBatchesWithPresent(TenMillion, TenMillion, HundredThousand)
-	if err != nil {
-		return nil, fmt.Errorf("failed to run insert batches with present test: %w", err)
+	if t.Config.ScenarioEnabled(scenarioSecondaryIndexes) {
+		var err error
+		results.SecondaryIndexes, err = t.testSecondaryIndexes(t.Config.Scaled(t.Config.SecondaryIndexes.TotalDocs))
+		if err != nil {
+			return nil, fmt.Errorf("failed to run secondary indexes test: %w", err)
+		}
 	}
 
+	results.Metadata.Duration = time.Now().Sub(results.Metadata.StartedAt)
 	return results, nil
 }
 
 type InsertBatchesTestResult struct {
-	ULIDDuration     time.Duration
-	UUIDDuration     time.Duration
-	ObjectIDDuration time.Duration
+	TotalDocs int `json:"totalDocs"`
+	BatchSize int `json:"batchSize"`
+	// Durations are keyed by the scheme name and hold a sample per trial.
+	Durations map[string][]time.Duration `json:"durationsNs"`
+	// Timelines are keyed by the scheme name and hold a timeline per trial.
+	Timelines map[string][]*BatchTimeline `json:"timelines"`
+	// EngineStats hold the changes of the WiredTiger counters, one per phase.
+	EngineStats []*PhaseEngineStats `json:"engineStats,omitempty"`
 }
 
 func (t *Tester) testInsertBatches(totalDocs, batchSize int) (*InsertBatchesTestResult, error) {
 	var start time.Time
 
-	result := new(InsertBatchesTestResult)
-
-	{
-		start = time.Now()
-		if err := t.insertDocumentsInBatches(batchSize, generateDocsUlid(totalDocs)); err != nil {
-			return nil, fmt.Errorf("error on insert documents in batches test run: %w", err)
-		}
-		result.ULIDDuration = time.Now().Sub(start)
-		if err := t.dropCollection(); err != nil {
-			return nil, fmt.Errorf("collection cleanup error: %w", err)
-		}
-	}
-
-	{
-		start = time.Now()
-		if err := t.insertDocumentsInBatches(batchSize, generateDocsUUID(totalDocs)); err != nil {
-			return nil, fmt.Errorf("error on insert documents in batches test run: %w", err)
-		}
-		result.UUIDDuration = time.Now().Sub(start)
-		if err := t.dropCollection(); err != nil {
-			return nil, fmt.Errorf("collection cleanup error: %w", err)
-		}
+	result := &InsertBatchesTestResult{
+		TotalDocs: totalDocs,
+		BatchSize: batchSize,
+		Durations: make(map[string][]time.Duration, len(t.Schemes)),
+		Timelines: make(map[string][]*BatchTimeline, len(t.Schemes)),
 	}
 
-	{
-		start = time.Now()
-		if err := t.insertDocumentsInBatches(batchSize, generateDocsObjectID(totalDocs)); err != nil {
-			return nil, fmt.Errorf("error on insert documents in batches test run: %w", err)
-		}
-		result.ObjectIDDuration = time.Now().Sub(start)
-		if err := t.dropCollection(); err != nil {
-			return nil, fmt.Errorf("collection cleanup error: %w", err)
+	for trial := 0; trial < t.Config.Repetitions; trial++ {
+		for _, scheme := range t.trialSchemes() {
+			docs := generateDocs(scheme, totalDocs, t.Payloads)
+			timeline := NewBatchTimeline()
+			err := t.recordPhase(&result.EngineStats, batchPhaseInsert, scheme.Name(), func() error {
+				start = time.Now()
+				if err := t.insertDocumentsInBatches(batchSize, docs, batchPhaseInsert, timeline); err != nil {
+					return err
+				}
+				result.Durations[scheme.Name()] = append(result.Durations[scheme.Name()], time.Now().Sub(start))
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error on insert documents in batches test run for %s: %w", scheme.Name(), err)
+			}
+			result.Timelines[scheme.Name()] = append(result.Timelines[scheme.Name()], timeline)
+			if err := t.dropCollection(); err != nil {
+				return nil, fmt.Errorf("collection cleanup error: %w", err)
+			}
 		}
 	}
 
@@ -134,46 +164,42 @@ func (t *Tester) testInsertBatches(totalDocs, batchSize int) (*InsertBatchesTest
 }
 
 type InsertTestResult struct {
-	ULIDDuration     time.Duration
-	UUIDDuration     time.Duration
-	ObjectIDDuration time.Duration
+	TotalDocs int `json:"totalDocs"`
+	// Durations are keyed by the scheme name and hold a sample per trial.
+	Durations map[string][]time.Duration `json:"durationsNs"`
+	// Latencies are keyed by the scheme name and hold the latencies of single inserts of all the trials.
+	Latencies map[string]*Histogram `json:"latencies"`
+	// EngineStats hold the changes of the WiredTiger counters, one per phase.
+	EngineStats []*PhaseEngineStats `json:"engineStats,omitempty"`
 }
 
 func (t *Tester) testInserts(totalDocs int) (*InsertTestResult, error) {
 	var start time.Time
 
-	result := new(InsertTestResult)
-
-	{
-		start = time.Now()
-		if err := t.insertDocuments(generateDocsUlid(totalDocs)); err != nil {
-			return nil, fmt.Errorf("error on insert documents test run: %w", err)
-		}
-		result.ULIDDuration = time.Now().Sub(start)
-		if err := t.dropCollection(); err != nil {
-			return nil, fmt.Errorf("collection cleanup error: %w", err)
-		}
+	result := &InsertTestResult{
+		TotalDocs: totalDocs,
+		Durations: make(map[string][]time.Duration, len(t.Schemes)),
+		Latencies: make(map[string]*Histogram, len(t.Schemes)),
 	}
 
-	{
-		start = time.Now()
-		if err := t.insertDocuments(generateDocsUUID(totalDocs)); err != nil {
-			return nil, fmt.Errorf("error on insert documents test run: %w", err)
-		}
-		result.UUIDDuration = time.Now().Sub(start)
-		if err := t.dropCollection(); err != nil {
-			return nil, fmt.Errorf("collection cleanup error: %w", err)
-		}
-	}
-
-	{
-		start = time.Now()
-		if err := t.insertDocuments(generateDocsObjectID(totalDocs)); err != nil {
-			return nil, fmt.Errorf("error on insert documents test run: %w", err)
-		}
-		result.ObjectIDDuration = time.Now().Sub(start)
-		if err := t.dropCollection(); err != nil {
-			return nil, fmt.Errorf("collection cleanup error: %w", err)
+	for trial := 0; trial < t.Config.Repetitions; trial++ {
+		for _, scheme := range t.trialSchemes() {
+			docs := generateDocs(scheme, totalDocs, t.Payloads)
+			latencies := histogramFor(result.Latencies, scheme.Name())
+			err := t.recordPhase(&result.EngineStats, batchPhaseInsert, scheme.Name(), func() error {
+				start = time.Now()
+				if err := t.insertDocuments(docs, latencies); err != nil {
+					return err
+				}
+				result.Durations[scheme.Name()] = append(result.Durations[scheme.Name()], time.Now().Sub(start))
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error on insert documents test run for %s: %w", scheme.Name(), err)
+			}
+			if err := t.dropCollection(); err != nil {
+				return nil, fmt.Errorf("collection cleanup error: %w", err)
+			}
 		}
 	}
 
@@ -181,139 +207,205 @@ func (t *Tester) testInserts(totalDocs int) (*InsertTestResult, error) {
 }
 
 type InsertBatchesWithPresentTestResult struct {
-	ObjectIDInsertDuration time.Duration
-	ObjectIDIdxSize        int64
-	ObjectIDGetDuration    time.Duration
-
-	ULIDInsertDuration time.Duration
-	ULIDIdxSize        int64
-	ULIDGetDuration    time.Duration
+	InsertCount  int `json:"insertCount"`
+	PresentCount int `json:"presentCount"`
+	BatchSize    int `json:"batchSize"`
+
+	// InsertDurations, IdxSizes and CollStats are keyed by the scheme name and hold a sample per trial,
+	// IdxSizes being the sizes of the _id index.
+	InsertDurations map[string][]time.Duration    `json:"insertDurationsNs"`
+	IdxSizes        map[string][]int64            `json:"idxSizesBytes"`
+	CollStats       map[string][]*CollectionStats `json:"collStats"`
+	// Gets hold the gets by ID of the present documents, one per key distribution.
+	Gets []*GetByIDResult `json:"gets,omitempty"`
+	// Timelines are keyed by the scheme name and hold a timeline per trial,
+	// covering both the provisioning with fixtures and the measured inserts.
+	Timelines map[string][]*BatchTimeline `json:"timelines"`
+	// Lookups hold the lookups of sets of IDs by $in, one per set size.
+	Lookups []*LookupResult `json:"lookups,omitempty"`
+	// Pagination holds the walks over the collection, one per page size.
+	Pagination []*PaginationResult `json:"pagination,omitempty"`
+	// RangeQueries hold the time range queries over the fixtures, one per range width.
+	RangeQueries []*TimeRangeQueryResult `json:"rangeQueries,omitempty"`
+	// EngineStats hold the changes of the WiredTiger counters, one per phase.
+	EngineStats []*PhaseEngineStats `json:"engineStats,omitempty"`
+}
 
-	UUIDInsertDuration time.Duration
-	UUIDIdxSize        int64
-	UUIDGetDuration    time.Duration
+// GetByIDResult holds the gets by ID of the keys picked with a single distribution.
+type GetByIDResult struct {
+	Distribution string `json:"distribution"`
+	// Durations are keyed by the scheme name and hold the average duration of a get, a sample per trial.
+	Durations map[string][]time.Duration `json:"durationsNs"`
+	// Latencies are keyed by the scheme name and hold the latencies of single gets of all the trials.
+	Latencies map[string]*Histogram `json:"latencies"`
 }
 
 func (t *Tester) testInsertBatchesWithPresent(insertCount, presentCount, batchSize int) (*InsertBatchesWithPresentTestResult, error) {
 	var start time.Time
 
-	result := new(InsertBatchesWithPresentTestResult)
-
-	const prepareBatchSize = 100000
-	const getProbes = 100
-
-	{
-		// provisioning with fixtures
-		fixtures := generateDocsUlid(presentCount)
-		if err := t.insertDocumentsInBatches(prepareBatchSize, fixtures); err != nil {
-			return nil, fmt.Errorf("error on insert documents in batches: %w", err)
-		}
+	result := &InsertBatchesWithPresentTestResult{
+		InsertCount:     insertCount,
+		PresentCount:    presentCount,
+		BatchSize:       batchSize,
+		InsertDurations: make(map[string][]time.Duration, len(t.Schemes)),
+		IdxSizes:        make(map[string][]int64, len(t.Schemes)),
+		CollStats:       make(map[string][]*CollectionStats, len(t.Schemes)),
+		Timelines:       make(map[string][]*BatchTimeline, len(t.Schemes)),
+	}
 
-		// inserting batches
-		start = time.Now()
-		if err := t.insertDocumentsInBatches(batchSize, generateDocsUlid(insertCount)); err != nil {
-			return nil, fmt.Errorf("error on insert documents in batches test run: %w", err)
-		}
-		result.ULIDInsertDuration = time.Now().Sub(start)
-
-		// getting random docs
-		getIDs := pickRandomULID(fixtures, getProbes)
-		start = time.Now()
-		for _, id := range getIDs {
-			if err := t.getDocumentByID(id); err != nil {
-				return nil, fmt.Errorf("error on getting document by id: %w", err)
+	prepareBatchSize := t.Config.InsertBatchesWithPresent.PrepareBatchSize
+	getProbes := t.Config.InsertBatchesWithPresent.GetProbes
+	includeDecode := t.Config.InsertBatchesWithPresent.GetIncludeDecode
+	rangeProbes := t.Config.InsertBatchesWithPresent.RangeProbes
+	rangeSpan := time.Duration(t.Config.InsertBatchesWithPresent.RangeSpan)
+	var getChoosers []keyChooser
+	if getProbes > 0 {
+		for _, distribution := range t.Config.InsertBatchesWithPresent.GetDistributions {
+			chooser, err := newKeyChooser(distribution, presentCount)
+			if err != nil {
+				return nil, err
 			}
-		}
-		result.ULIDGetDuration = time.Now().Sub(start) / getProbes
-
-		// getting the index size
-		if idxSize, err := t.getDefaultIDIndexSize(); err != nil {
-			return nil, fmt.Errorf("failed to get default id index size: %w", err)
-		} else {
-			result.ULIDIdxSize = idxSize
-		}
-
-		if err := t.dropCollection(); err != nil {
-			return nil, fmt.Errorf("collection cleanup error: %w", err)
+			getChoosers = append(getChoosers, chooser)
+			result.Gets = append(result.Gets, &GetByIDResult{
+				Distribution: distribution,
+				Durations:    make(map[string][]time.Duration, len(t.Schemes)),
+				Latencies:    make(map[string]*Histogram, len(t.Schemes)),
+			})
 		}
 	}
+	lookupProbes := t.Config.InsertBatchesWithPresent.LookupProbes
+	if lookupProbes > 0 {
+		result.Lookups = newLookupResults(t.Config.InsertBatchesWithPresent.LookupSizes, len(t.Schemes))
+	}
+	result.Pagination = newPaginationResults(t.Config.InsertBatchesWithPresent.PageSizes, len(t.Schemes))
+	if rangeProbes > 0 {
+		result.RangeQueries = newTimeRangeQueryResults(t.Config.InsertBatchesWithPresent.RangeWidths, len(t.Schemes))
+	}
 
-	{
-		// provisioning with fixtures
-		fixtures := generateDocsUUID(presentCount)
-		if err := t.insertDocumentsInBatches(prepareBatchSize, fixtures); err != nil {
-			return nil, fmt.Errorf("error on insert documents in batches: %w", err)
-		}
-
-		// inserting batches
-		start = time.Now()
-		if err := t.insertDocumentsInBatches(batchSize, generateDocsUUID(insertCount)); err != nil {
-			return nil, fmt.Errorf("error on insert documents in batches test run: %w", err)
-		}
-		result.UUIDInsertDuration = time.Now().Sub(start)
-
-		// getting random docs
-		getIDs := pickRandomUUID(fixtures, getProbes)
-		start = time.Now()
-		for _, id := range getIDs {
-			if err := t.getDocumentByID(id); err != nil {
-				return nil, fmt.Errorf("error on getting document by id: %w", err)
+	for trial := 0; trial < t.Config.Repetitions; trial++ {
+		for _, scheme := range t.trialSchemes() {
+			// provisioning with fixtures
+			fixtures := generateDocs(scheme, presentCount, t.Payloads)
+			name := scheme.Name()
+			timeline := NewBatchTimeline()
+			err := t.recordPhase(&result.EngineStats, batchPhasePrepare, name, func() error {
+				return t.insertDocumentsInBatches(prepareBatchSize, fixtures, batchPhasePrepare, timeline)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error on insert documents in batches for %s: %w", name, err)
 			}
-		}
-		result.UUIDGetDuration = time.Now().Sub(start) / getProbes
 
-		// getting the index size
-		if idxSize, err := t.getDefaultIDIndexSize(); err != nil {
-			return nil, fmt.Errorf("failed to get default id index size: %w", err)
-		} else {
-			result.UUIDIdxSize = idxSize
-		}
+			// inserting batches
+			docs := generateDocs(scheme, insertCount, t.Payloads)
+			err = t.recordPhase(&result.EngineStats, batchPhaseInsert, name, func() error {
+				start = time.Now()
+				if err := t.insertDocumentsInBatches(batchSize, docs, batchPhaseInsert, timeline); err != nil {
+					return err
+				}
+				result.InsertDurations[name] = append(result.InsertDurations[name], time.Now().Sub(start))
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error on insert documents in batches test run for %s: %w", name, err)
+			}
+			result.Timelines[name] = append(result.Timelines[name], timeline)
+
+			// getting docs picked with every key distribution
+			if len(result.Gets) > 0 {
+				err = t.recordPhase(&result.EngineStats, phaseGet, name, func() error {
+					docType := scheme.DocumentType()
+					r := rand.New(rand.NewSource(time.Now().UnixNano()))
+					// the keys are picked among all the present documents, the inserted ones are the latest
+					present := insertionOrder(fixtures, docs)
+					for i, get := range result.Gets {
+						getIDs := pickIDs(scheme, present, getProbes, getChoosers[i], r)
+						latencies := histogramFor(get.Latencies, name)
+						var total time.Duration
+						for _, id := range getIDs {
+							latency, err := t.getDocumentByID(docType, id, includeDecode)
+							if err != nil {
+								return err
+							}
+							latencies.Record(latency)
+							total += latency
+						}
+						get.Durations[name] = append(get.Durations[name], total/time.Duration(getProbes))
+					}
+					return nil
+				})
+				if err != nil {
+					return nil, fmt.Errorf("error on getting document by id: %w", err)
+				}
+			}
 
-		if err := t.dropCollection(); err != nil {
-			return nil, fmt.Errorf("collection cleanup error: %w", err)
-		}
-	}
+			// looking up sets of docs by $in
+			if lookupProbes > 0 && len(result.Lookups) > 0 {
+				err = t.recordPhase(&result.EngineStats, phaseLookup, name, func() error {
+					return t.lookupIDs(scheme, fixtures, result.Lookups, lookupProbes)
+				})
+				if err != nil {
+					return nil, fmt.Errorf("error on looking up IDs for %s: %w", name, err)
+				}
+			}
 
-	{
-		// provisioning with fixtures
-		fixtures := generateDocsObjectID(presentCount)
-		if err := t.insertDocumentsInBatches(prepareBatchSize, fixtures); err != nil {
-			return nil, fmt.Errorf("error on insert documents in batches: %w", err)
-		}
+			// walking the collection in pages
+			if len(result.Pagination) > 0 {
+				err = t.recordPhase(&result.EngineStats, phasePagination, name, func() error {
+					return t.walkPages(scheme, result.Pagination)
+				})
+				if err != nil {
+					return nil, fmt.Errorf("error on walking pages for %s: %w", name, err)
+				}
+			}
 
-		// inserting batches
-		start = time.Now()
-		if err := t.insertDocumentsInBatches(batchSize, generateDocsObjectID(insertCount)); err != nil {
-			return nil, fmt.Errorf("error on insert documents in batches test run: %w", err)
-		}
-		result.ObjectIDInsertDuration = time.Now().Sub(start)
-
-		// getting random docs
-		getIDs := pickRandomObjectID(fixtures, getProbes)
-		start = time.Now()
-		for _, id := range getIDs {
-			if err := t.getDocumentByID(id); err != nil {
-				return nil, fmt.Errorf("error on getting document by id: %w", err)
+			// querying time ranges of as many documents as are present, created over the range span
+			if rangeProbes > 0 {
+				err = t.testTimeRanges(scheme, result.RangeQueries, &result.EngineStats, rangeProbes, presentCount, prepareBatchSize, rangeSpan)
+				if err != nil {
+					return nil, fmt.Errorf("error on querying time ranges for %s: %w", name, err)
+				}
 			}
-		}
-		result.ObjectIDGetDuration = time.Now().Sub(start) / getProbes
 
-		// getting the index size
-		if idxSize, err := t.getDefaultIDIndexSize(); err != nil {
-			return nil, fmt.Errorf("failed to get default id index size: %w", err)
-		} else {
-			result.ObjectIDIdxSize = idxSize
-		}
+			// getting the collection and index sizes
+			stats, err := t.getCollectionStats()
+			if err != nil {
+				return nil, err
+			}
+			result.CollStats[name] = append(result.CollStats[name], stats)
+			result.IdxSizes[name] = append(result.IdxSizes[name], stats.IndexSizes[idIndexName])
 
-		if err := t.dropCollection(); err != nil {
-			return nil, fmt.Errorf("collection cleanup error: %w", err)
+			if err := t.dropCollection(); err != nil {
+				return nil, fmt.Errorf("collection cleanup error: %w", err)
+			}
 		}
 	}
 
 	return result, nil
 }
 
-func (t *Tester) insertDocumentsInBatches(batchSize int, docs []interface{}) error {
+// histogramFor returns the histogram of the scheme creating it when missing.
+func histogramFor(m map[string]*Histogram, scheme string) *Histogram {
+	h, ok := m[scheme]
+	if !ok {
+		h = NewHistogram()
+		m[scheme] = h
+	}
+	return h
+}
+
+// trialSchemes returns the tested schemes in random order, so that the order
+// the schemes are run in within a trial does not bias the results.
+func (t *Tester) trialSchemes() []IDScheme {
+	result := make([]IDScheme, len(t.Schemes))
+	copy(result, t.Schemes)
+	rand.Shuffle(len(result), func(i, j int) {
+		result[i], result[j] = result[j], result[i]
+	})
+	return result
+}
+
+// insertDocumentsInBatches inserts the documents in batches recording every batch in the timeline under the given phase.
+func (t *Tester) insertDocumentsInBatches(batchSize int, docs []interface{}, phase string, timeline *BatchTimeline) error {
 
 	totalDocs := len(docs)
 
@@ -324,38 +416,58 @@ func (t *Tester) insertDocumentsInBatches(batchSize int, docs []interface{}) err
 		} else {
 			end = start + batchSize
 		}
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second+t.Payloads.writeTimeout(end-start))
+		batchStart := time.Now()
 		_, err := t.Coll.InsertMany(ctx, docs[start:end])
+		batchDuration := time.Now().Sub(batchStart)
 		cancel()
 		if err != nil {
 			return fmt.Errorf("error inserting documents in batch: %w", err)
 		}
+		timeline.Record(phase, end-start, batchStart, batchDuration)
 	}
 	return nil
 }
 
-func (t *Tester) insertDocuments(docs []interface{}) error {
+// insertDocuments inserts the documents one by one recording the latency of every insert.
+func (t *Tester) insertDocuments(docs []interface{}, latencies *Histogram) error {
 	totalDocs := len(docs)
 
 	for i := 0; i < totalDocs; i += 1 {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		start := time.Now()
 		_, err := t.Coll.InsertOne(ctx, docs[i])
+		latency := time.Now().Sub(start)
 		cancel()
 		if err != nil {
 			return fmt.Errorf("error inserting document: %w", err)
 		}
+		latencies.Record(latency)
 	}
 	return nil
 }
 
-func (t *Tester) getDocumentByID(id interface{}) error {
+// getDocumentByID reads the document with FindOne and decodes it into the document type of the scheme,
+// it returns the latency of the read which includes the decoding only when includeDecode is set.
+func (t *Tester) getDocumentByID(docType reflect.Type, id interface{}, includeDecode bool) (time.Duration, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	_, err := t.Coll.Find(ctx, bson.M{"_id": id})
-	cancel()
-	if err != nil {
-		return fmt.Errorf("error getting document: %w", err)
+	defer cancel()
+
+	start := time.Now()
+	res := t.Coll.FindOne(ctx, bson.M{"_id": id})
+	if err := res.Err(); err != nil {
+		return 0, fmt.Errorf("error getting document: %w", err)
 	}
-	return nil
+	latency := time.Now().Sub(start)
+
+	doc := reflect.New(docType)
+	if err := res.Decode(doc.Interface()); err != nil {
+		return 0, fmt.Errorf("error decoding document: %w", err)
+	}
+	if includeDecode {
+		latency = time.Now().Sub(start)
+	}
+	return latency, nil
 }
 
 func (t *Tester) dropCollection() error {
@@ -367,60 +479,6 @@ func (t *Tester) dropCollection() error {
 	return nil
 }
 
-func (t *Tester) getDefaultIDIndexSize() (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	res := t.Coll.Database().RunCommand(ctx, bson.M{"collStats": "perftest"})
-
-	var document bson.M
-	if err := res.Decode(&document); err != nil {
-		return 0, fmt.Errorf("%w", err)
-	}
-
-	idxSizes, ok := document["indexSizes"]
-	if !ok {
-		return 0, fmt.Errorf("indexSizes key not found")
-	}
-
-	size, ok := idxSizes.(bson.M)["_id_"]
-	if !ok {
-		return 0, fmt.Errorf("_id_ key not found")
-	}
-
-	return int64(size.(int32)), nil
-}
-
-func generateDocsUlid(n int) []interface{} {
-	result := make([]interface{}, n)
-	for i := 0; i < n; i++ {
-		result[i] = mongoDocumentULID{
-			ID: ulid.Make(),
-		}
-	}
-	return result
-}
-
-func generateDocsUUID(n int) []interface{} {
-	result := make([]interface{}, n)
-	for i := 0; i < n; i++ {
-		result[i] = mongoDocumentUUID{
-			ID: uuid.New(),
-		}
-	}
-	return result
-}
-
-func generateDocsObjectID(n int) []interface{} {
-	result := make([]interface{}, n)
-	for i := 0; i < n; i++ {
-		result[i] = mongoDocumentObjectID{
-			ID: primitive.NewObjectID(),
-		}
-	}
-	return result
-}
-
 func byteCountIEC(b int64) string {
 	const unit = 1024
 	if b < unit {
@@ -435,36 +493,6 @@ func byteCountIEC(b int64) string {
 		float64(b)/float64(div), "KMGTPE"[exp])
 }
 
-func pickRandomULID(docs []interface{}, n int) []ulid.ULID {
-	docsLen := len(docs)
-	var result = make([]ulid.ULID, n)
-	for i := 0; i < n; i++ {
-		d := docs[rand.Intn(docsLen)]
-		result[i] = d.(mongoDocumentULID).ID
-	}
-	return result
-}
-
-func pickRandomUUID(docs []interface{}, n int) []uuid.UUID {
-	docsLen := len(docs)
-	var result = make([]uuid.UUID, n)
-	for i := 0; i < n; i++ {
-		d := docs[rand.Intn(docsLen)]
-		result[i] = d.(mongoDocumentUUID).ID
-	}
-	return result
-}
-
-func pickRandomObjectID(docs []interface{}, n int) []primitive.ObjectID {
-	docsLen := len(docs)
-	var result = make([]primitive.ObjectID, n)
-	for i := 0; i < n; i++ {
-		d := docs[rand.Intn(docsLen)]
-		result[i] = d.(mongoDocumentObjectID).ID
-	}
-	return result
-}
-
 func calcDiffPercent(baseline, newVal int64) float64 {
 	var k float64 = 1
 	if newVal > baseline {
@@ -473,3 +501,9 @@ func calcDiffPercent(baseline, newVal int64) float64 {
 
 	return k * (float64(newVal) - float64(baseline)) * 100 / float64(baseline)
 }
+
+// calcRateDiffPercent returns the difference of the rate from the baseline one in percent,
+// it is positive when the rate is higher as higher rates are the better ones.
+func calcRateDiffPercent(baseline, newVal int64) float64 {
+	return (float64(newVal) - float64(baseline)) * 100 / float64(baseline)
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	generatorShared    = "shared"
+	generatorPerWorker = "per-worker"
+)
+
+var allGenerators = []string{generatorShared, generatorPerWorker}
+
+// maxInsertRetries is the number of times a batch insert failed with a transient error is retried.
+const maxInsertRetries = 5
+
+const (
+	writeConflictErrorCode = 112
+	duplicateKeyErrorCode  = 11000
+)
+
+type ConcurrentInsertsTestResult struct {
+	TotalDocs int `json:"totalDocs"`
+	BatchSize int `json:"batchSize"`
+	Workers   int `json:"workers"`
+	// Generator is either "shared" when all the workers use the same ID generator
+	// or "per-worker" when every worker has a generator of its own.
+	Generator string `json:"generator"`
+
+	// Durations, WriteConflicts and Retries are keyed by the scheme name and hold a sample per trial.
+	Durations map[string][]time.Duration `json:"durationsNs"`
+	// WriteConflicts are the write conflicts the server encountered, taken from serverStatus.
+	WriteConflicts map[string][]int64 `json:"writeConflicts"`
+	// Retries are the batch inserts retried by the workers after transient errors.
+	Retries map[string][]int64 `json:"retries"`
+	// EngineStats hold the changes of the WiredTiger counters, one per phase.
+	EngineStats []*PhaseEngineStats `json:"engineStats,omitempty"`
+}
+
+func (t *Tester) testConcurrentInserts(totalDocs, batchSize, workers int, generator string) (*ConcurrentInsertsTestResult, error) {
+	result := &ConcurrentInsertsTestResult{
+		TotalDocs:      totalDocs,
+		BatchSize:      batchSize,
+		Workers:        workers,
+		Generator:      generator,
+		Durations:      make(map[string][]time.Duration, len(t.Schemes)),
+		WriteConflicts: make(map[string][]int64, len(t.Schemes)),
+		Retries:        make(map[string][]int64, len(t.Schemes)),
+	}
+
+	for trial := 0; trial < t.Config.Repetitions; trial++ {
+		for _, scheme := range t.trialSchemes() {
+			before, err := t.getServerStatus()
+			if err != nil {
+				return nil, err
+			}
+
+			var retries int64
+			var duration time.Duration
+			err = t.recordPhase(&result.EngineStats, batchPhaseInsert, scheme.Name(), func() error {
+				start := time.Now()
+				var err error
+				retries, err = t.insertDocumentsConcurrently(scheme, totalDocs, batchSize, workers, generator == generatorPerWorker)
+				duration = time.Now().Sub(start)
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error on concurrent insert documents test run for %s: %w", scheme.Name(), err)
+			}
+
+			after, err := t.getServerStatus()
+			if err != nil {
+				return nil, err
+			}
+
+			name := scheme.Name()
+			result.Durations[name] = append(result.Durations[name], duration)
+			result.Retries[name] = append(result.Retries[name], retries)
+			result.WriteConflicts[name] = append(result.WriteConflicts[name],
+				after.Metrics.Operation.WriteConflicts-before.Metrics.Operation.WriteConflicts)
+
+			if err = t.dropCollection(); err != nil {
+				return nil, fmt.Errorf("collection cleanup error: %w", err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// insertDocumentsConcurrently inserts totalDocs documents in batches by the given number of workers.
+// The workers generate the documents right before inserting them, using either the shared scheme
+// or a copy of their own. It returns the number of retried batches.
+func (t *Tester) insertDocumentsConcurrently(scheme IDScheme, totalDocs, batchSize, workers int, perWorker bool) (int64, error) {
+	batches := int64((totalDocs + batchSize - 1) / batchSize)
+	var nextBatch, retries int64
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		s := scheme
+		if perWorker {
+			s = schemeForWorker(scheme, w)
+		}
+
+		wg.Add(1)
+		go func(s IDScheme) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				batch := atomic.AddInt64(&nextBatch, 1) - 1
+				if batch >= batches {
+					return
+				}
+				n := batchSize
+				if rest := totalDocs - int(batch)*batchSize; rest < n {
+					n = rest
+				}
+
+				r, err := t.insertBatchWithRetries(ctx, generateDocs(s, n, t.Payloads))
+				atomic.AddInt64(&retries, r)
+				if err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+			}
+		}(s)
+	}
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return retries, err
+	}
+	return retries, nil
+}
+
+// insertBatchWithRetries inserts the documents retrying on write conflicts and transient errors,
+// it returns the number of retries. Retries are unordered, so that the documents which made it
+// into the collection before a failure are skipped as duplicates while the rest get inserted.
+func (t *Tester) insertBatchWithRetries(ctx context.Context, docs []interface{}) (int64, error) {
+	var retries int64
+	for {
+		opts := mongooptions.InsertMany().SetOrdered(retries == 0)
+		insertCtx, cancel := context.WithTimeout(ctx, 30*time.Second+t.Payloads.writeTimeout(len(docs)))
+		_, err := t.Coll.InsertMany(insertCtx, docs, opts)
+		cancel()
+
+		switch {
+		case err == nil:
+			return retries, nil
+		case retries > 0 && isDuplicateKeyOnlyError(err):
+			return retries, nil
+		case retries < maxInsertRetries && isTransientWriteError(err):
+			retries++
+		default:
+			return retries, fmt.Errorf("error inserting documents in batch: %w", err)
+		}
+	}
+}
+
+// isDuplicateKeyOnlyError reports whether all the writes of a bulk write failed due to duplicate keys.
+func isDuplicateKeyOnlyError(err error) bool {
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) || bwe.WriteConcernError != nil || len(bwe.WriteErrors) == 0 {
+		return false
+	}
+	for _, we := range bwe.WriteErrors {
+		if we.Code != duplicateKeyErrorCode {
+			return false
+		}
+	}
+	return true
+}
+
+func isTransientWriteError(err error) bool {
+	if mongo.IsNetworkError(err) || mongo.IsTimeout(err) {
+		return true
+	}
+	var se mongo.ServerError
+	if errors.As(err, &se) {
+		return se.HasErrorCode(writeConflictErrorCode) ||
+			se.HasErrorLabel("RetryableWriteError") ||
+			se.HasErrorLabel("TransientTransactionError")
+	}
+	return false
+}
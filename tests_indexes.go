@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// indexFieldTenant is the field holding the tenant of the documents of the secondary indexes scenario.
+	indexFieldTenant = "tenantId"
+	// indexFieldRef is the field holding the identifier of another document, the way foreign keys do.
+	indexFieldRef = "refId"
+)
+
+type SecondaryIndexesTestResult struct {
+	TotalDocs int `json:"totalDocs"`
+	BatchSize int `json:"batchSize"`
+	Tenants   int `json:"tenants"`
+
+	// Durations are keyed by the scheme name and hold the durations of the inserts into the collection
+	// having the _id index only, a sample per trial.
+	Durations map[string][]time.Duration `json:"durationsNs"`
+	// IndexedDurations are keyed by the scheme name and hold the durations of the inserts into the collection
+	// having the secondary indexes too, a sample per trial.
+	IndexedDurations map[string][]time.Duration `json:"indexedDurationsNs"`
+	// Overheads are keyed by the scheme name and hold the differences of IndexedDurations and Durations, a sample per trial.
+	Overheads map[string][]time.Duration `json:"overheadsNs"`
+	// Indexes hold the sizes of every index of the collection, the _id index first.
+	Indexes []*IndexSizeResult `json:"indexes"`
+	// CollStats are keyed by the scheme name and hold the stats of the collection with the secondary indexes,
+	// a sample per trial.
+	CollStats map[string][]*CollectionStats `json:"collStats"`
+	// EngineStats hold the changes of the WiredTiger counters, one per phase.
+	EngineStats []*PhaseEngineStats `json:"engineStats,omitempty"`
+}
+
+// IndexSizeResult holds the sizes of a single index.
+type IndexSizeResult struct {
+	Name string `json:"name"`
+	// Keys is the spec the index was created with, e.g. tenantId:1,_id:-1.
+	Keys string `json:"keys"`
+	// Sizes are keyed by the scheme name and hold a sample per trial.
+	Sizes map[string][]int64 `json:"sizesBytes"`
+}
+
+// testSecondaryIndexes inserts the same documents, having a tenant and a reference to another document,
+// once into the collection with the _id index only and once into the collection with the secondary indexes
+// created beforehand, and measures the sizes of all the indexes.
+func (t *Tester) testSecondaryIndexes(totalDocs int) (*SecondaryIndexesTestResult, error) {
+	c := t.Config.SecondaryIndexes
+	result := &SecondaryIndexesTestResult{
+		TotalDocs:        totalDocs,
+		BatchSize:        c.BatchSize,
+		Tenants:          c.Tenants,
+		Durations:        make(map[string][]time.Duration, len(t.Schemes)),
+		IndexedDurations: make(map[string][]time.Duration, len(t.Schemes)),
+		Overheads:        make(map[string][]time.Duration, len(t.Schemes)),
+		CollStats:        make(map[string][]*CollectionStats, len(t.Schemes)),
+	}
+
+	keys := make([]bson.D, len(c.Indexes))
+	for i, spec := range c.Indexes {
+		var err error
+		if keys[i], err = parseIndexKeys(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	for trial := 0; trial < t.Config.Repetitions; trial++ {
+		for _, scheme := range t.trialSchemes() {
+			name := scheme.Name()
+			docs := generateReferencingDocs(scheme, totalDocs, c.Tenants, t.Payloads)
+
+			var plain, indexed time.Duration
+			err := t.recordPhase(&result.EngineStats, batchPhaseInsert, name, func() error {
+				start := time.Now()
+				err := t.insertDocumentsInBatches(c.BatchSize, docs, batchPhaseInsert, NewBatchTimeline())
+				plain = time.Now().Sub(start)
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error on insert documents in batches for %s: %w", name, err)
+			}
+			if err = t.dropCollection(); err != nil {
+				return nil, fmt.Errorf("collection cleanup error: %w", err)
+			}
+
+			indexNames, err := t.createIndexes(keys)
+			if err != nil {
+				return nil, err
+			}
+			err = t.recordPhase(&result.EngineStats, phaseIndexedInsert, name, func() error {
+				start := time.Now()
+				err := t.insertDocumentsInBatches(c.BatchSize, docs, batchPhaseInsert, NewBatchTimeline())
+				indexed = time.Now().Sub(start)
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error on insert documents in batches with secondary indexes for %s: %w", name, err)
+			}
+
+			stats, err := t.getCollectionStats()
+			if err != nil {
+				return nil, err
+			}
+			result.CollStats[name] = append(result.CollStats[name], stats)
+			if result.Indexes == nil {
+				result.Indexes = append(result.Indexes, &IndexSizeResult{Name: idIndexName, Keys: "_id:1"})
+				for i, indexName := range indexNames {
+					result.Indexes = append(result.Indexes, &IndexSizeResult{Name: indexName, Keys: c.Indexes[i]})
+				}
+				for _, idx := range result.Indexes {
+					idx.Sizes = make(map[string][]int64, len(t.Schemes))
+				}
+			}
+			for _, idx := range result.Indexes {
+				idx.Sizes[name] = append(idx.Sizes[name], stats.IndexSizes[idx.Name])
+			}
+
+			result.Durations[name] = append(result.Durations[name], plain)
+			result.IndexedDurations[name] = append(result.IndexedDurations[name], indexed)
+			result.Overheads[name] = append(result.Overheads[name], indexed-plain)
+
+			if err = t.dropCollection(); err != nil {
+				return nil, fmt.Errorf("collection cleanup error: %w", err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// generateReferencingDocs generates n documents of the given number of tenants,
+// every document refers to a random document generated before it or to itself for the first one.
+func generateReferencingDocs(s IDScheme, n, tenants int, payloads *payloadGenerator) []interface{} {
+	ids := make([]interface{}, n)
+	result := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		ids[i] = s.NewID()
+		payload := payloads.Next()
+		if payload == nil {
+			payload = make(Payload, 2)
+		}
+		payload[indexFieldTenant] = int32(rand.Intn(tenants))
+		payload[indexFieldRef] = ids[rand.Intn(i+1)]
+		result[i] = s.NewDocument(ids[i], payload)
+	}
+	return result
+}
+
+// createIndexes creates the indexes with the given keys and returns their names.
+func (t *Tester) createIndexes(keys []bson.D) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
+	defer cancel()
+
+	models := make([]mongo.IndexModel, len(keys))
+	for i, k := range keys {
+		models[i] = mongo.IndexModel{Keys: k}
+	}
+	names, err := t.Coll.Indexes().CreateMany(ctx, models)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create indexes: %w", err)
+	}
+	return names, nil
+}
+
+// parseIndexKeys parses the comma-separated fields of an index spec having the direction after a colon,
+// e.g. tenantId:1,_id:-1.
+func parseIndexKeys(spec string) (bson.D, error) {
+	var keys bson.D
+	for _, item := range splitList(spec) {
+		field, direction, ok := strings.Cut(item, ":")
+		if !ok || field == "" {
+			return nil, fmt.Errorf("invalid index key %q of %q, expected field:direction", item, spec)
+		}
+		d, err := strconv.Atoi(direction)
+		if err != nil || (d != 1 && d != -1) {
+			return nil, fmt.Errorf("invalid direction of index key %q of %q, expected 1 or -1", item, spec)
+		}
+		keys = append(keys, bson.E{Key: field, Value: d})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("empty index spec %q", spec)
+	}
+	return keys, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParseIndexKeys(t *testing.T) {
+	keys, err := parseIndexKeys("tenantId:1, _id:-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (bson.D{{Key: "tenantId", Value: 1}, {Key: "_id", Value: -1}}); !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+
+	for _, spec := range []string{"", "tenantId", ":1", "tenantId:0", "tenantId:up", "tenantId:1,refId"} {
+		if _, err = parseIndexKeys(spec); err == nil {
+			t.Errorf("index spec %q is accepted", spec)
+		}
+	}
+}
+
+func TestGenerateReferencingDocs(t *testing.T) {
+	s := new(objectIDScheme)
+	docs := generateReferencingDocs(s, 100, 3, nil)
+
+	seen := make(map[interface{}]bool, len(docs))
+	for i, doc := range docs {
+		d := doc.(mongoDocumentObjectID)
+		seen[d.ID] = true
+		// every document refers to itself or to a document generated before it
+		if !seen[d.Payload[indexFieldRef]] {
+			t.Fatalf("document %d refers to %v generated after it", i, d.Payload[indexFieldRef])
+		}
+		if tenant := d.Payload[indexFieldTenant].(int32); tenant < 0 || tenant >= 3 {
+			t.Fatalf("tenant %d of document %d is out of [0, 3)", tenant, i)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// LookupResult holds the lookups of sets of random IDs of a single size.
+type LookupResult struct {
+	Size int `json:"size"`
+	// Durations are keyed by the scheme name and hold the average duration of a lookup, a sample per trial.
+	Durations map[string][]time.Duration `json:"durationsNs"`
+	// Latencies are keyed by the scheme name and hold the latencies of the lookups of all the trials.
+	Latencies map[string]*Histogram `json:"latencies"`
+}
+
+func newLookupResults(sizes []int, schemes int) []*LookupResult {
+	result := make([]*LookupResult, len(sizes))
+	for i, size := range sizes {
+		result[i] = &LookupResult{
+			Size:      size,
+			Durations: make(map[string][]time.Duration, schemes),
+			Latencies: make(map[string]*Histogram, schemes),
+		}
+	}
+	return result
+}
+
+// lookupIDs issues the given number of lookups of sets of distinct random IDs of every size,
+// every lookup is a single Find by $in of the set reading all the found documents.
+func (t *Tester) lookupIDs(scheme IDScheme, docs []interface{}, results []*LookupResult, probes int) error {
+	for _, res := range results {
+		latencies := histogramFor(res.Latencies, scheme.Name())
+		var total time.Duration
+		for i := 0; i < probes; i++ {
+			ids := pickDistinctIDs(scheme, docs, res.Size)
+
+			start := time.Now()
+			n, err := t.countFound(bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}})
+			if err != nil {
+				return fmt.Errorf("error on looking up %d IDs: %w", len(ids), err)
+			}
+			latency := time.Now().Sub(start)
+			if n != int64(len(ids)) {
+				return fmt.Errorf("looked up %d IDs, found %d documents", len(ids), n)
+			}
+			latencies.Record(latency)
+			total += latency
+		}
+		if probes > 0 {
+			res.Durations[scheme.Name()] = append(res.Durations[scheme.Name()], total/time.Duration(probes))
+		}
+	}
+	return nil
+}
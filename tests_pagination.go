@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PaginationResult holds the walks over the whole collection in pages of a single size.
+type PaginationResult struct {
+	PageSize int `json:"pageSize"`
+	// WalkDurations are keyed by the scheme name and hold a sample per trial.
+	WalkDurations map[string][]time.Duration `json:"walkDurationsNs"`
+	// PageLatencies are keyed by the scheme name and hold the latencies of the pages of all the trials,
+	// including the decoding of the documents.
+	PageLatencies map[string]*Histogram `json:"pageLatencies"`
+	// Pages are keyed by the scheme name and hold the number of pages walked, a sample per trial.
+	Pages map[string][]int64 `json:"pages"`
+}
+
+func newPaginationResults(pageSizes []int, schemes int) []*PaginationResult {
+	result := make([]*PaginationResult, len(pageSizes))
+	for i, pageSize := range pageSizes {
+		result[i] = &PaginationResult{
+			PageSize:      pageSize,
+			WalkDurations: make(map[string][]time.Duration, schemes),
+			PageLatencies: make(map[string]*Histogram, schemes),
+			Pages:         make(map[string][]int64, schemes),
+		}
+	}
+	return result
+}
+
+// walkPages reads the whole collection sorted by _id in pages of every size, every page but the first
+// one is selected by the _id greater than the last one seen, decoded into the document of the scheme.
+func (t *Tester) walkPages(scheme IDScheme, results []*PaginationResult) error {
+	docType := scheme.DocumentType()
+
+	for _, res := range results {
+		latencies := histogramFor(res.PageLatencies, scheme.Name())
+		var pages int64
+		var lastID interface{}
+
+		start := time.Now()
+		for {
+			pageStart := time.Now()
+			id, n, err := t.readPage(scheme, docType, lastID, res.PageSize)
+			if err != nil {
+				return fmt.Errorf("error on reading page %d: %w", pages+1, err)
+			}
+			latencies.Record(time.Now().Sub(pageStart))
+			pages++
+
+			if n < res.PageSize {
+				break
+			}
+			lastID = id
+		}
+		res.WalkDurations[scheme.Name()] = append(res.WalkDurations[scheme.Name()], time.Now().Sub(start))
+		res.Pages[scheme.Name()] = append(res.Pages[scheme.Name()], pages)
+	}
+	return nil
+}
+
+// readPage reads the page of documents following the afterID, the first page is read when it is nil.
+// It returns the identifier of the last document of the page and the number of documents in the page.
+func (t *Tester) readPage(scheme IDScheme, docType reflect.Type, afterID interface{}, pageSize int) (interface{}, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	filter := bson.D{}
+	if afterID != nil {
+		filter = bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: afterID}}}}
+	}
+	opts := mongooptions.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(pageSize))
+
+	cur, err := t.Coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cur.Close(ctx)
+
+	var lastID interface{}
+	var n int
+	for cur.Next(ctx) {
+		doc := reflect.New(docType)
+		if err = cur.Decode(doc.Interface()); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode document: %w", err)
+		}
+		lastID = scheme.DocumentID(doc.Elem().Interface())
+		n++
+	}
+	return lastID, n, cur.Err()
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestCalcDiffPercent(t *testing.T) {
+	tests := []struct {
+		baseline, newVal int64
+		diff, rateDiff   float64
+	}{
+		{100, 100, 0, 0},
+		{100, 80, -20, -20},
+		{100, 125, -25, 25},
+		{200, 100, -50, -50},
+		{200, 600, -200, 200},
+	}
+	for _, tt := range tests {
+		if got := calcDiffPercent(tt.baseline, tt.newVal); got != tt.diff {
+			t.Errorf("calcDiffPercent(%d, %d) = %v, want %v", tt.baseline, tt.newVal, got, tt.diff)
+		}
+		if got := calcRateDiffPercent(tt.baseline, tt.newVal); got != tt.rateDiff {
+			t.Errorf("calcRateDiffPercent(%d, %d) = %v, want %v", tt.baseline, tt.newVal, got, tt.rateDiff)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	rangeFieldID        = "_id"
+	rangeFieldCreatedAt = "createdAt"
+)
+
+// TimeRangeQueryResult holds the queries selecting the documents created within time ranges of a single width.
+type TimeRangeQueryResult struct {
+	Width time.Duration `json:"widthNs"`
+	// Fields are keyed by the scheme name and hold the field the ranges were selected by,
+	// "_id" for the time ordered schemes and "createdAt" for the rest of them.
+	Fields map[string]string `json:"fields"`
+	// Latencies are keyed by the scheme name and hold the latencies of the queries of all the trials,
+	// every query reads all the matching documents.
+	Latencies map[string]*Histogram `json:"latencies"`
+	// KeysExamined and DocsReturned are keyed by the scheme name and hold
+	// the average per query, a sample per trial.
+	KeysExamined map[string][]int64 `json:"keysExamined"`
+	DocsReturned map[string][]int64 `json:"docsReturned"`
+}
+
+func newTimeRangeQueryResults(widths []Duration, schemes int) []*TimeRangeQueryResult {
+	result := make([]*TimeRangeQueryResult, len(widths))
+	for i, width := range widths {
+		result[i] = &TimeRangeQueryResult{
+			Width:        time.Duration(width),
+			Fields:       make(map[string]string, schemes),
+			Latencies:    make(map[string]*Histogram, schemes),
+			KeysExamined: make(map[string][]int64, schemes),
+			DocsReturned: make(map[string][]int64, schemes),
+		}
+	}
+	return result
+}
+
+// rangeCollectionSuffix is appended to the name of the collection of the scenario to name the collection
+// the time range queries are run against.
+const rangeCollectionSuffix = "_ranges"
+
+// testTimeRanges fills a collection of its own with docsCount documents created over the span ending now
+// and queries time ranges of it, so that neither the documents carrying their creation time nor the createdAt
+// index built when the scheme identifiers are not time ordered affect the collection of the scenario.
+// The changes of the WiredTiger counters over the queries are recorded into stats.
+func (t *Tester) testTimeRanges(scheme IDScheme, results []*TimeRangeQueryResult, stats *[]*PhaseEngineStats,
+	probes, docsCount, batchSize int, span time.Duration) error {
+	rt := t.withCollection(t.Coll.Name() + rangeCollectionSuffix)
+
+	end := time.Now()
+	docs := generateTimedDocs(scheme, docsCount, end, span, t.Payloads)
+	err := rt.insertDocumentsInBatches(batchSize, docs, batchPhasePrepare, NewBatchTimeline())
+	if err == nil {
+		if _, ok := scheme.(timeOrderedScheme); !ok {
+			err = rt.createIndex(bson.D{{Key: rangeFieldCreatedAt, Value: 1}})
+		}
+	}
+	if err == nil {
+		err = rt.recordPhase(stats, phaseRange, scheme.Name(), func() error {
+			return rt.queryTimeRanges(scheme, results, probes, end, span)
+		})
+	}
+
+	if dropErr := rt.dropCollection(); err == nil {
+		err = dropErr
+	}
+	return err
+}
+
+// withCollection returns a copy of the tester working with the named collection of the same database.
+func (t *Tester) withCollection(name string) *Tester {
+	c := *t
+	c.Coll = t.Coll.Database().Collection(name)
+	return &c
+}
+
+// queryTimeRanges issues the given number of queries for random time ranges within the span ending at end
+// for every width, the collection has to hold the documents made by generateTimedDocs for the span
+// and a createdAt index when the scheme identifiers are not time ordered.
+func (t *Tester) queryTimeRanges(scheme IDScheme, results []*TimeRangeQueryResult, probes int, end time.Time, span time.Duration) error {
+	start := end.Add(-span)
+	for _, res := range results {
+		latencies := histogramFor(res.Latencies, scheme.Name())
+		var keysExamined, docsReturned int64
+		for i := 0; i < probes; i++ {
+			from := start.Add(time.Duration(rand.Int63n(int64(span-res.Width) + 1)))
+			filter, field := timeRangeFilter(scheme, from, from.Add(res.Width))
+			res.Fields[scheme.Name()] = field
+
+			queryStart := time.Now()
+			if _, err := t.countFound(filter); err != nil {
+				return fmt.Errorf("error on querying time range: %w", err)
+			}
+			latencies.Record(time.Now().Sub(queryStart))
+
+			stats, err := t.explainFind(filter)
+			if err != nil {
+				return err
+			}
+			keysExamined += stats.TotalKeysExamined
+			docsReturned += stats.NReturned
+		}
+		if probes > 0 {
+			res.KeysExamined[scheme.Name()] = append(res.KeysExamined[scheme.Name()], keysExamined/int64(probes))
+			res.DocsReturned[scheme.Name()] = append(res.DocsReturned[scheme.Name()], docsReturned/int64(probes))
+		}
+	}
+	return nil
+}
+
+// timeRangeFilter returns the filter selecting the documents created within [from, to) along with the field
+// it selects by: the _id range bounded by the lowest identifiers of from and to for the time ordered
+// schemes, which is as precise as the timestamps embedded into the identifiers, or createdAt otherwise.
+func timeRangeFilter(scheme IDScheme, from, to time.Time) (bson.D, string) {
+	if ts, ok := scheme.(timeOrderedScheme); ok {
+		return bson.D{{Key: rangeFieldID, Value: bson.D{
+			{Key: "$gte", Value: ts.MinIDAt(from)},
+			{Key: "$lt", Value: ts.MinIDAt(to)},
+		}}}, rangeFieldID
+	}
+	return bson.D{{Key: rangeFieldCreatedAt, Value: bson.D{
+		{Key: "$gte", Value: from},
+		{Key: "$lt", Value: to},
+	}}}, rangeFieldCreatedAt
+}
+
+// countFound finds the documents matching the filter and reads all of them, returning their number.
+func (t *Tester) countFound(filter interface{}) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	cur, err := t.Coll.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cur.Close(ctx)
+
+	var n int64
+	for cur.Next(ctx) {
+		n++
+	}
+	return n, cur.Err()
+}
+
+// executionStats holds the explain executionStats counters the tests are interested in.
+type executionStats struct {
+	NReturned         int64 `bson:"nReturned"`
+	TotalKeysExamined int64 `bson:"totalKeysExamined"`
+	TotalDocsExamined int64 `bson:"totalDocsExamined"`
+}
+
+func (t *Tester) explainFind(filter interface{}) (*executionStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var explain struct {
+		ExecutionStats executionStats `bson:"executionStats"`
+	}
+	res := t.Coll.Database().RunCommand(ctx, bson.D{
+		{Key: "explain", Value: bson.D{
+			{Key: "find", Value: t.Coll.Name()},
+			{Key: "filter", Value: filter},
+		}},
+		{Key: "verbosity", Value: "executionStats"},
+	})
+	if err := res.Decode(&explain); err != nil {
+		return nil, fmt.Errorf("failed to explain find: %w", err)
+	}
+	return &explain.ExecutionStats, nil
+}
+
+func (t *Tester) createIndex(keys bson.D) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
+	defer cancel()
+	if _, err := t.Coll.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys}); err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	operationUpdateOne  = "updateOne"
+	operationReplaceOne = "replaceOne"
+	operationDeleteOne  = "deleteOne"
+	operationDeleteMany = "deleteMany"
+)
+
+const (
+	keySelectionRandom = "random"
+	keySelectionRecent = "recent"
+)
+
+type UpdatesDeletesTestResult struct {
+	PresentCount int `json:"presentCount"`
+	// RecentCount is the number of the most recently inserted documents the recent keys are picked from,
+	// the random keys are picked from the rest of the documents.
+	RecentCount int `json:"recentCount"`
+	Ops         int `json:"ops"`
+	// DeleteManySize is the number of documents deleted by every DeleteMany call.
+	DeleteManySize int `json:"deleteManySize"`
+
+	Operations []*KeyedOperationResult `json:"operations"`
+	// EngineStats hold the changes of the WiredTiger counters, one per phase.
+	EngineStats []*PhaseEngineStats `json:"engineStats,omitempty"`
+}
+
+// KeyedOperationResult holds the operations of a single type over the keys of a single selection.
+type KeyedOperationResult struct {
+	Operation string `json:"operation"`
+	// Selection is either "random" or "recent".
+	Selection string `json:"selection"`
+	// Durations are keyed by the scheme name and hold the average duration of an operation, a sample per trial.
+	Durations map[string][]time.Duration `json:"durationsNs"`
+	// Latencies are keyed by the scheme name and hold the latencies of the operations of all the trials.
+	Latencies map[string]*Histogram `json:"latencies"`
+}
+
+func (t *Tester) testUpdatesDeletes(presentCount, recentCount, ops int) (*UpdatesDeletesTestResult, error) {
+	c := t.Config.UpdatesDeletes
+	result := &UpdatesDeletesTestResult{
+		PresentCount:   presentCount,
+		RecentCount:    recentCount,
+		Ops:            ops,
+		DeleteManySize: c.DeleteManySize,
+	}
+
+	operations := make(map[string]*KeyedOperationResult)
+	for _, selection := range []string{keySelectionRandom, keySelectionRecent} {
+		for _, operation := range []string{operationUpdateOne, operationReplaceOne, operationDeleteOne, operationDeleteMany} {
+			r := &KeyedOperationResult{
+				Operation: operation,
+				Selection: selection,
+				Durations: make(map[string][]time.Duration, len(t.Schemes)),
+				Latencies: make(map[string]*Histogram, len(t.Schemes)),
+			}
+			operations[selection+"|"+operation] = r
+			result.Operations = append(result.Operations, r)
+		}
+	}
+
+	for trial := 0; trial < t.Config.Repetitions; trial++ {
+		for _, scheme := range t.trialSchemes() {
+			fixtures := generateDocs(scheme, presentCount, t.Payloads)
+			err := t.recordPhase(&result.EngineStats, batchPhasePrepare, scheme.Name(), func() error {
+				return t.insertDocumentsInBatches(c.PrepareBatchSize, fixtures, batchPhasePrepare, NewBatchTimeline())
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error on insert documents in batches for %s: %w", scheme.Name(), err)
+			}
+
+			older, recent := fixtures[:presentCount-recentCount], fixtures[presentCount-recentCount:]
+			for _, sel := range []struct {
+				name string
+				docs []interface{}
+			}{
+				{keySelectionRandom, older},
+				{keySelectionRecent, recent},
+			} {
+				// the deleted documents are distinct, so that every delete has a document to delete
+				deleted := pickDistinctIDs(scheme, sel.docs, ops*(1+c.DeleteManySize))
+
+				err = t.recordPhase(&result.EngineStats, phaseOperations+"-"+sel.name, scheme.Name(), func() error {
+					for _, run := range []struct {
+						operation string
+						keys      []interface{}
+						op        func(ctx context.Context, keys []interface{}) error
+					}{
+						{operationUpdateOne, pickRandomIDs(scheme, sel.docs, ops), t.updateOneByID},
+						{operationReplaceOne, pickRandomIDs(scheme, sel.docs, ops), func(ctx context.Context, keys []interface{}) error {
+							return t.replaceOneByID(ctx, scheme, keys)
+						}},
+						{operationDeleteOne, deleted[:ops], t.deleteOneByID},
+						{operationDeleteMany, deleted[ops:], t.deleteManyByIDs},
+					} {
+						res := operations[sel.name+"|"+run.operation]
+						groupSize := 1
+						if run.operation == operationDeleteMany {
+							groupSize = c.DeleteManySize
+						}
+						avg, err := t.timeKeyedOperations(run.keys, groupSize, histogramFor(res.Latencies, scheme.Name()), run.op)
+						if err != nil {
+							return fmt.Errorf("error on %s of %s keys for %s: %w", run.operation, sel.name, scheme.Name(), err)
+						}
+						res.Durations[scheme.Name()] = append(res.Durations[scheme.Name()], avg)
+					}
+					return nil
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if err = t.dropCollection(); err != nil {
+				return nil, fmt.Errorf("collection cleanup error: %w", err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// timeKeyedOperations runs the operation for every group of groupSize keys recording its latency,
+// it returns the average duration of an operation.
+func (t *Tester) timeKeyedOperations(keys []interface{}, groupSize int, latencies *Histogram,
+	op func(ctx context.Context, keys []interface{}) error) (time.Duration, error) {
+	var total time.Duration
+	var n int
+	for i := 0; i < len(keys); i += groupSize {
+		end := i + groupSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		start := time.Now()
+		err := op(ctx, keys[i:end])
+		latency := time.Now().Sub(start)
+		cancel()
+		if err != nil {
+			return 0, err
+		}
+		latencies.Record(latency)
+		total += latency
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return total / time.Duration(n), nil
+}
+
+func (t *Tester) updateOneByID(ctx context.Context, keys []interface{}) error {
+	_, err := t.Coll.UpdateOne(ctx, bson.D{{Key: "_id", Value: keys[0]}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "updatedAt", Value: time.Now()}}}})
+	return err
+}
+
+func (t *Tester) replaceOneByID(ctx context.Context, scheme IDScheme, keys []interface{}) error {
+	_, err := t.Coll.ReplaceOne(ctx, bson.D{{Key: "_id", Value: keys[0]}}, scheme.NewDocument(keys[0], t.Payloads.Next()))
+	return err
+}
+
+func (t *Tester) deleteOneByID(ctx context.Context, keys []interface{}) error {
+	_, err := t.Coll.DeleteOne(ctx, bson.D{{Key: "_id", Value: keys[0]}})
+	return err
+}
+
+func (t *Tester) deleteManyByIDs(ctx context.Context, keys []interface{}) error {
+	_, err := t.Coll.DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: keys}}}})
+	return err
+}
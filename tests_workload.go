@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	workloadInsert = "insert"
+	workloadRead   = "read"
+	workloadUpdate = "update"
+	workloadScan   = "scan"
+)
+
+var allWorkloadOperations = []string{workloadInsert, workloadRead, workloadUpdate, workloadScan}
+
+type MixedWorkloadTestResult struct {
+	PresentCount int           `json:"presentCount"`
+	Duration     time.Duration `json:"durationNs"`
+	Workers      int           `json:"workers"`
+	// Distribution is the distribution the keys of the reads, updates and scans are picked with.
+	Distribution string      `json:"distribution"`
+	Mix          WorkloadMix `json:"mix"`
+
+	// Throughputs are keyed by the scheme name and hold the operations of all types per second, a sample per trial.
+	Throughputs map[string][]float64 `json:"throughputs"`
+	// Operations are keyed by the operation type.
+	Operations map[string]*WorkloadOperationResult `json:"operations"`
+	// EngineStats hold the changes of the WiredTiger counters, one per phase.
+	EngineStats []*PhaseEngineStats `json:"engineStats,omitempty"`
+}
+
+type WorkloadOperationResult struct {
+	// Throughputs are keyed by the scheme name and hold the operations per second, a sample per trial.
+	Throughputs map[string][]float64 `json:"throughputs"`
+	// Latencies are keyed by the scheme name and hold the latencies of the operations of all the trials.
+	Latencies map[string]*Histogram `json:"latencies"`
+}
+
+func (t *Tester) testMixedWorkload(presentCount int, distribution string) (*MixedWorkloadTestResult, error) {
+	c := t.Config.MixedWorkload
+	result := &MixedWorkloadTestResult{
+		PresentCount: presentCount,
+		Duration:     time.Duration(c.Duration),
+		Workers:      c.Workers,
+		Distribution: distribution,
+		Mix:          c.Mix,
+		Throughputs:  make(map[string][]float64, len(t.Schemes)),
+		Operations:   make(map[string]*WorkloadOperationResult, len(allWorkloadOperations)),
+	}
+	for _, op := range allWorkloadOperations {
+		result.Operations[op] = &WorkloadOperationResult{
+			Throughputs: make(map[string][]float64, len(t.Schemes)),
+			Latencies:   make(map[string]*Histogram, len(t.Schemes)),
+		}
+	}
+
+	for trial := 0; trial < t.Config.Repetitions; trial++ {
+		for _, scheme := range t.trialSchemes() {
+			fixtures := generateDocs(scheme, presentCount, t.Payloads)
+			err := t.recordPhase(&result.EngineStats, batchPhasePrepare, scheme.Name(), func() error {
+				return t.insertDocumentsInBatches(c.PrepareBatchSize, fixtures, batchPhasePrepare, NewBatchTimeline())
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error on insert documents in batches for %s: %w", scheme.Name(), err)
+			}
+
+			keys := &workloadKeys{ids: make([]interface{}, len(fixtures))}
+			for i, doc := range fixtures {
+				keys.ids[i] = scheme.DocumentID(doc)
+			}
+			chooser, err := newKeyChooser(distribution, presentCount)
+			if err != nil {
+				return nil, err
+			}
+
+			var stats map[string]*workloadOperationStats
+			var elapsed time.Duration
+			err = t.recordPhase(&result.EngineStats, phaseWorkload, scheme.Name(), func() error {
+				var err error
+				stats, elapsed, err = t.runWorkload(scheme, keys, chooser)
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error on mixed workload test run for %s: %w", scheme.Name(), err)
+			}
+
+			name := scheme.Name()
+			var total int64
+			for _, op := range allWorkloadOperations {
+				res := result.Operations[op]
+				res.Throughputs[name] = append(res.Throughputs[name], float64(stats[op].count)/elapsed.Seconds())
+				histogramFor(res.Latencies, name).Merge(stats[op].latencies)
+				total += stats[op].count
+			}
+			result.Throughputs[name] = append(result.Throughputs[name], float64(total)/elapsed.Seconds())
+
+			if err = t.dropCollection(); err != nil {
+				return nil, fmt.Errorf("collection cleanup error: %w", err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// workloadKeys holds the identifiers of the documents in the collection in the order of insertion.
+type workloadKeys struct {
+	mu  sync.RWMutex
+	ids []interface{}
+}
+
+func (k *workloadKeys) Len() int {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return len(k.ids)
+}
+
+func (k *workloadKeys) Get(i int) interface{} {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.ids[i]
+}
+
+func (k *workloadKeys) Append(id interface{}) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.ids = append(k.ids, id)
+}
+
+type workloadOperationStats struct {
+	count     int64
+	latencies *Histogram
+}
+
+// runWorkload runs the operations of the configured mix by the configured number of workers
+// for the configured duration, the inserted keys become available to the other operations.
+// It returns the stats per operation type and the time elapsed until all the workers stopped.
+func (t *Tester) runWorkload(scheme IDScheme, keys *workloadKeys, chooser keyChooser) (map[string]*workloadOperationStats, time.Duration, error) {
+	c := t.Config.MixedWorkload
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.Duration))
+	defer cancel()
+
+	workerStats := make([]map[string]*workloadOperationStats, c.Workers)
+	errs := make(chan error, c.Workers)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < c.Workers; w++ {
+		stats := make(map[string]*workloadOperationStats, len(allWorkloadOperations))
+		for _, op := range allWorkloadOperations {
+			stats[op] = &workloadOperationStats{latencies: NewHistogram()}
+		}
+		workerStats[w] = stats
+
+		wg.Add(1)
+		go func(s IDScheme, r *rand.Rand) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				op := c.Mix.pick(r)
+				opStart := time.Now()
+				if err := t.runWorkloadOperation(op, s, keys, chooser, r); err != nil {
+					errs <- fmt.Errorf("%s failed: %w", op, err)
+					cancel()
+					return
+				}
+				stats[op].latencies.Record(time.Now().Sub(opStart))
+				stats[op].count++
+			}
+		}(schemeForWorker(scheme, w), rand.New(rand.NewSource(time.Now().UnixNano()+int64(w))))
+	}
+	wg.Wait()
+	elapsed := time.Now().Sub(start)
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return nil, 0, err
+	}
+
+	result := make(map[string]*workloadOperationStats, len(allWorkloadOperations))
+	for _, op := range allWorkloadOperations {
+		total := &workloadOperationStats{latencies: NewHistogram()}
+		for _, stats := range workerStats {
+			total.count += stats[op].count
+			total.latencies.Merge(stats[op].latencies)
+		}
+		result[op] = total
+	}
+	return result, elapsed, nil
+}
+
+func (t *Tester) runWorkloadOperation(op string, s IDScheme, keys *workloadKeys, chooser keyChooser, r *rand.Rand) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	switch op {
+	case workloadInsert:
+		id := s.NewID()
+		if _, err := t.Coll.InsertOne(ctx, s.NewDocument(id, t.Payloads.Next())); err != nil {
+			return err
+		}
+		keys.Append(id)
+		return nil
+	case workloadRead:
+		var doc bson.Raw
+		return t.Coll.FindOne(ctx, bson.D{{Key: "_id", Value: keys.Get(chooser.Next(r, keys.Len()))}}).Decode(&doc)
+	case workloadUpdate:
+		_, err := t.Coll.UpdateOne(ctx, bson.D{{Key: "_id", Value: keys.Get(chooser.Next(r, keys.Len()))}},
+			bson.D{{Key: "$set", Value: bson.D{{Key: "updatedAt", Value: time.Now()}}}})
+		return err
+	case workloadScan:
+		filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$gte", Value: keys.Get(chooser.Next(r, keys.Len()))}}}}
+		opts := mongooptions.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(t.Config.MixedWorkload.ScanLength))
+		cur, err := t.Coll.Find(ctx, filter, opts)
+		if err != nil {
+			return err
+		}
+		defer cur.Close(ctx)
+		for cur.Next(ctx) {
+		}
+		return cur.Err()
+	default:
+		return fmt.Errorf("unknown operation %q", op)
+	}
+}
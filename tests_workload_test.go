@@ -0,0 +1,40 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParseWorkloadMix(t *testing.T) {
+	mix, err := parseWorkloadMix("insert=0.1, read = 0.5,update=0.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (WorkloadMix{Insert: 0.1, Read: 0.5, Update: 0.4}); mix != want {
+		t.Errorf("mix = %+v, want %+v", mix, want)
+	}
+
+	for _, s := range []string{"insert", "insert=x", "delete=0.5"} {
+		if _, err = parseWorkloadMix(s); err == nil {
+			t.Errorf("mix %q is accepted", s)
+		}
+	}
+}
+
+func TestWorkloadMixPick(t *testing.T) {
+	mix := WorkloadMix{Insert: 1, Read: 2, Update: 0, Scan: 1}
+	r := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	for i := 0; i < 40000; i++ {
+		counts[mix.pick(r)]++
+	}
+	want := map[string]int{workloadInsert: 10000, workloadRead: 20000, workloadScan: 10000}
+	for op, n := range want {
+		if d := counts[op] - n; d < -n/10 || d > n/10 {
+			t.Errorf("%s is picked %d times, want about %d", op, counts[op], n)
+		}
+	}
+	if counts[workloadUpdate] != 0 {
+		t.Errorf("%s of zero proportion is picked %d times", workloadUpdate, counts[workloadUpdate])
+	}
+}
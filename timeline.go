@@ -0,0 +1,52 @@
+package main
+
+import "time"
+
+const (
+	batchPhasePrepare = "prepare"
+	batchPhaseInsert  = "insert"
+)
+
+// BatchTimeline records the throughput of every batch inserted into a collection,
+// showing how the insert performance changes while the collection grows.
+type BatchTimeline struct {
+	start          time.Time
+	collectionDocs int
+
+	Samples []BatchSample `json:"samples"`
+}
+
+// BatchSample describes a single InsertMany call.
+type BatchSample struct {
+	// Phase is either "prepare" for the batches of the fixtures or "insert" for the measured ones.
+	Phase string `json:"phase"`
+	// Offset is the time elapsed from the start of the timeline to the start of the batch.
+	Offset   time.Duration `json:"offsetNs"`
+	Duration time.Duration `json:"durationNs"`
+	Docs     int           `json:"docs"`
+	// CollectionDocs is the number of documents in the collection after the batch is inserted.
+	CollectionDocs int     `json:"collectionDocs"`
+	DocsPerSecond  float64 `json:"docsPerSecond"`
+}
+
+func NewBatchTimeline() *BatchTimeline {
+	return &BatchTimeline{start: time.Now()}
+}
+
+// Record adds the batch of docs which insertion started at start and took d.
+func (tl *BatchTimeline) Record(phase string, docs int, start time.Time, d time.Duration) {
+	tl.collectionDocs += docs
+
+	var docsPerSecond float64
+	if d > 0 {
+		docsPerSecond = float64(docs) / d.Seconds()
+	}
+	tl.Samples = append(tl.Samples, BatchSample{
+		Phase:          phase,
+		Offset:         start.Sub(tl.start),
+		Duration:       d,
+		Docs:           docs,
+		CollectionDocs: tl.collectionDocs,
+		DocsPerSecond:  docsPerSecond,
+	})
+}